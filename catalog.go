@@ -0,0 +1,149 @@
+package problems
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+const (
+	// CatalogPath is the suggested well-known path for publishing the catalog
+	CatalogPath = "/.well-known/problem-types"
+	// CatalogMediaType is the media type of the catalog document
+	CatalogMediaType = "application/json"
+)
+
+// Catalog is the machine-readable list of problem types served by CatalogHandler
+type Catalog struct {
+	// Version identifies the revision of the catalog
+	Version string `json:"version"`
+	// Types are the registered problem types sorted by type URI
+	Types []CatalogEntry `json:"types"`
+}
+
+// CatalogEntry describes a single problem type in the catalog
+type CatalogEntry struct {
+	Type       string                 `json:"type"`
+	Status     int                    `json:"status,omitempty"`
+	Title      string                 `json:"title"`
+	Href       string                 `json:"href,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// NewCatalog builds the catalog for the registry.  When a resolver is
+// provided each entry without its own href includes the resolved
+// documentation URL.  An empty version uses the registry's version.
+func NewCatalog(version string, reg *Registry, res *Resolver) Catalog {
+	if reg == nil {
+		reg = DefaultRegistry
+	}
+	if version == "" {
+		version = reg.Version()
+	}
+	catalog := Catalog{Version: version, Types: make([]CatalogEntry, 0)}
+	for _, info := range reg.Types() {
+		entry := CatalogEntry{
+			Type:   info.Type,
+			Status: info.Status,
+			Title:  info.Title,
+		}
+		if info.Href != "" {
+			entry.Href = info.Href
+		} else if res != nil {
+			if href := res.Resolve(info.Type); href != info.Type {
+				entry.Href = href
+			}
+		}
+		if len(info.Extensions) > 0 {
+			entry.Extensions = make(map[string]interface{})
+			for _, ext := range info.Extensions {
+				entry.Extensions[ext.Name] = ext.Schema()
+			}
+		}
+		catalog.Types = append(catalog.Types, entry)
+	}
+	return catalog
+}
+
+// Schema returns the JSON schema of the extension member
+func (ext Extension) Schema() map[string]interface{} {
+	schema := make(map[string]interface{})
+	if ext.Type != "" {
+		schema["type"] = ext.Type
+	}
+	if ext.Description != "" {
+		schema["description"] = ext.Description
+	}
+	if ext.Example != nil {
+		schema["example"] = ext.Example
+	}
+	return schema
+}
+
+// ETag returns a strong entity tag derived from the catalog contents
+func (catalog Catalog) ETag() string {
+	body, _ := json.Marshal(catalog)
+	return etag(body)
+}
+
+func etag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// CatalogHandler serves the problem type catalog as JSON.  Responses carry
+// an ETag so clients can revalidate with If-None-Match.
+type CatalogHandler struct {
+	// Version is published as the catalog version
+	Version  string
+	Registry *Registry
+	Resolver *Resolver
+}
+
+// NewCatalogHandler creates a catalog handler.  A nil registry uses the
+// DefaultRegistry and a nil resolver omits the href of each type.
+func NewCatalogHandler(version string, reg *Registry, res *Resolver) *CatalogHandler {
+	return &CatalogHandler{Version: version, Registry: reg, Resolver: res}
+}
+
+// ServeHTTP writes the catalog or a 304 if the client's copy is current
+func (h *CatalogHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		prob := New(http.StatusMethodNotAllowed, "The problem type catalog is read-only")
+		prob.Header().Set("Allow", "GET, HEAD")
+		_ = prob.Render(w, r)
+		return
+	}
+	body, err := json.Marshal(NewCatalog(h.Version, h.Registry, h.Resolver))
+	if err != nil {
+		_ = FromError(err).Render(w, r)
+		return
+	}
+	tag := etag(body)
+	w.Header().Set("ETag", tag)
+	w.Header().Set("Cache-Control", "public, max-age=0, must-revalidate")
+	if etagMatches(r.Header.Get("If-None-Match"), tag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", CatalogMediaType)
+	if r.Method == http.MethodHead {
+		return
+	}
+	var out bytes.Buffer
+	_ = json.Indent(&out, body, "", "  ")
+	_, _ = out.WriteTo(w)
+}
+
+func etagMatches(header, tag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package problems
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCatalogHandler(t *testing.T) {
+	reg := NewRegistry()
+	_ = reg.Register(TypeInfo{
+		Type:       "urn:problem-type:example:test",
+		Title:      "Test Problem",
+		Status:     409,
+		Extensions: []Extension{{Name: "traceId", Type: "string"}},
+	})
+	handler := NewCatalogHandler("1.0", reg, NewResolver("https://api.example.com/problems"))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, CatalogPath, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("CatalogHandler status = %v, want %v", w.Code, http.StatusOK)
+	}
+	var catalog Catalog
+	if err := json.Unmarshal(w.Body.Bytes(), &catalog); err != nil {
+		t.Fatalf("CatalogHandler body is not a catalog: %v", err)
+	}
+	if catalog.Version != "1.0" || len(catalog.Types) != 1 {
+		t.Fatalf("CatalogHandler catalog = %+v", catalog)
+	}
+	if got := catalog.Types[0].Href; got != "https://api.example.com/problems/example/test" {
+		t.Errorf("CatalogHandler href = %v", got)
+	}
+	if _, ok := catalog.Types[0].Extensions["traceId"]; !ok {
+		t.Errorf("CatalogHandler extensions = %v", catalog.Types[0].Extensions)
+	}
+
+	tag := w.Header().Get("ETag")
+	if tag == "" {
+		t.Fatal("CatalogHandler did not set an ETag")
+	}
+	req := httptest.NewRequest(http.MethodGet, CatalogPath, nil)
+	req.Header.Set("If-None-Match", tag)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusNotModified {
+		t.Errorf("CatalogHandler revalidation status = %v, want %v", w.Code, http.StatusNotModified)
+	}
+}
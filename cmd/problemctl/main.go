@@ -0,0 +1,189 @@
+// Command problemctl formats, validates, converts and explains problem
+// documents.
+//
+//	problemctl [-types file.json] fmt [file]
+//	problemctl [-types file.json] validate [file]
+//	problemctl [-types file.json] convert -to json|xml|text [file]
+//	problemctl [-types file.json] explain type-uri
+//
+// Documents are read from the named file or stdin.  The standard problem
+// types are always known; -types loads additional type files and may be
+// repeated.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"tjdavis.dev/problems"
+	_ "tjdavis.dev/problems/standard"
+)
+
+type typeFiles []string
+
+func (files *typeFiles) String() string {
+	return strings.Join(*files, ",")
+}
+
+func (files *typeFiles) Set(path string) error {
+	*files = append(*files, path)
+	return nil
+}
+
+const usage = `usage: problemctl [-types file.json] command [arguments]
+
+commands:
+  fmt [file]                         normalize a JSON problem
+  validate [file]                    check a problem against RFC7807 and the type catalog
+  convert -to json|xml|text [file]   convert a JSON problem to another format
+  explain type-uri                   print the documentation for a problem type
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	var types typeFiles
+	flags := flag.NewFlagSet("problemctl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Var(&types, "types", "load additional problem types from a type `file`")
+	flags.Usage = func() { fmt.Fprint(stderr, usage) }
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	for _, path := range types {
+		if err := problems.DefaultRegistry.LoadFile(path); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	command, args := flags.Arg(0), flags.Args()[1:]
+	var err error
+	switch command {
+	case "fmt":
+		err = format(args, stdin, stdout)
+	case "validate":
+		var ok bool
+		ok, err = validate(args, stdin, stdout)
+		if err == nil && !ok {
+			return 1
+		}
+	case "convert":
+		err = convert(args, stdin, stdout, stderr)
+	case "explain":
+		err = explain(args, stdout)
+	default:
+		fmt.Fprintf(stderr, "problemctl: unknown command %q\n", command)
+		flags.Usage()
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "problemctl:", err)
+		return 1
+	}
+	return 0
+}
+
+// readInput reads the file named by the only argument or stdin
+func readInput(args []string, stdin io.Reader) ([]byte, error) {
+	switch len(args) {
+	case 0:
+		return io.ReadAll(stdin)
+	case 1:
+		if args[0] == "-" {
+			return io.ReadAll(stdin)
+		}
+		return os.ReadFile(args[0])
+	default:
+		return nil, fmt.Errorf("expected a single file, got %d", len(args))
+	}
+}
+
+func readProblem(args []string, stdin io.Reader) (*problems.Problem, error) {
+	data, err := readInput(args, stdin)
+	if err != nil {
+		return nil, err
+	}
+	prob := &problems.Problem{}
+	if err := prob.UnmarshalJSON(data); err != nil {
+		return nil, fmt.Errorf("invalid problem document: %v", err)
+	}
+	return prob, nil
+}
+
+func format(args []string, stdin io.Reader, stdout io.Writer) error {
+	prob, err := readProblem(args, stdin)
+	if err != nil {
+		return err
+	}
+	return prob.Fprint(stdout)
+}
+
+func convert(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("convert", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	to := flags.String("to", "json", "the output format: json, xml or text")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	prob, err := readProblem(flags.Args(), stdin)
+	if err != nil {
+		return err
+	}
+	var out []byte
+	switch *to {
+	case "json":
+		out, err = prob.MarshalJSON()
+	case "xml":
+		out, err = prob.MarshalXML()
+	case "text":
+		out = []byte(text(prob))
+	default:
+		return fmt.Errorf("unknown format %q; use json, xml or text", *to)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(stdout, strings.TrimRight(string(out), "\n"))
+	return err
+}
+
+func explain(args []string, stdout io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("explain requires a single type URI")
+	}
+	info, ok := problems.LookupType(args[0])
+	if !ok {
+		info, ok = problems.LookupType(problems.DefaultTypePrefix + args[0])
+	}
+	if !ok {
+		return fmt.Errorf("%s is not a known problem type", args[0])
+	}
+	fmt.Fprintf(stdout, "%s\n\n", info.Title)
+	fmt.Fprintf(stdout, "Type:    %s\n", info.Type)
+	fmt.Fprintf(stdout, "Status:  %d %s\n", info.Status, http.StatusText(info.Status))
+	if info.Href != "" {
+		fmt.Fprintf(stdout, "Href:    %s\n", info.Href)
+	}
+	if info.Description != "" {
+		fmt.Fprintf(stdout, "\n%s\n", info.Description)
+	}
+	if len(info.Extensions) > 0 {
+		fmt.Fprintln(stdout, "\nExtension members:")
+		for _, ext := range info.Extensions {
+			fmt.Fprintf(stdout, "  %-20s %-8s %s\n", strings.ToLower(ext.Name), ext.Type, ext.Description)
+		}
+	}
+	fmt.Fprintln(stdout, "\nExample:")
+	return info.Example().Fprint(stdout)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		input    string
+		wantCode int
+		wantOut  string
+	}{
+		{
+			name:     "Test fmt",
+			args:     []string{"fmt"},
+			input:    `{"status": 404, "detail": "No user 123"}`,
+			wantCode: 0,
+			wantOut:  `"title": "Not Found"`,
+		},
+		{
+			name:     "Test valid problem",
+			args:     []string{"validate"},
+			input:    `{"type": "urn:problem-type:missingScope", "status": 403, "title": "Missing Scope", "requiredscopes": ["a"]}`,
+			wantCode: 0,
+			wantOut:  "ok",
+		},
+		{
+			name:     "Test status mismatch",
+			args:     []string{"validate"},
+			input:    `{"type": "urn:problem-type:missingScope", "status": 400, "title": "Missing Scope"}`,
+			wantCode: 1,
+			wantOut:  "error: status 400 doesn't match the catalog status 403",
+		},
+		{
+			name:     "Test extension on about:blank",
+			args:     []string{"validate"},
+			input:    `{"status": 400, "title": "Bad Request", "traceid": "1"}`,
+			wantCode: 1,
+			wantOut:  "error: extension member traceid requires a type other than about:blank",
+		},
+		{
+			name:     "Test convert to xml",
+			args:     []string{"convert", "-to", "xml"},
+			input:    `{"type": "urn:problem-type:missingScope", "status": 403, "requiredscopes": ["a"]}`,
+			wantCode: 0,
+			wantOut:  "<requiredscopes>\n    <i>a</i>\n  </requiredscopes>",
+		},
+		{
+			name:     "Test convert to text",
+			args:     []string{"convert", "-to", "text"},
+			input:    `{"status": 404, "detail": "No user 123"}`,
+			wantCode: 0,
+			wantOut:  "404 Not Found\nNo user 123",
+		},
+		{
+			name:     "Test explain",
+			args:     []string{"explain", "urn:problem-type:noAccessToken"},
+			wantCode: 0,
+			wantOut:  "Status:  401 Unauthorized",
+		},
+		{
+			name:     "Test unknown command",
+			args:     []string{"lint"},
+			wantCode: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(tt.args, strings.NewReader(tt.input), &stdout, &stderr)
+			if code != tt.wantCode {
+				t.Errorf("run() = %v, want %v; stderr %s", code, tt.wantCode, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.wantOut) {
+				t.Errorf("run() output = %s, want %s", stdout.String(), tt.wantOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"tjdavis.dev/problems"
+)
+
+// text renders the problem for reading in a terminal or a ticket
+func text(prob *problems.Problem) string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "%d %s\n", prob.Status, prob.GetTitle())
+	if prob.Detail != "" {
+		fmt.Fprintf(&out, "%s\n", prob.Detail)
+	}
+	out.WriteString("\n")
+	typeURI := prob.Type
+	if typeURI == "" {
+		typeURI = "about:blank"
+	}
+	fmt.Fprintf(&out, "type:     %s\n", typeURI)
+	if prob.Instance != "" {
+		fmt.Fprintf(&out, "instance: %s\n", prob.Instance)
+	}
+	fields := prob.ExtraFields()
+	sort.Strings(fields)
+	for _, name := range fields {
+		value := prob.Get(name)
+		if s, ok := value.(string); ok {
+			fmt.Fprintf(&out, "%s: %s\n", name, s)
+			continue
+		}
+		data, err := json.MarshalIndent(value, "  ", "  ")
+		if err != nil {
+			fmt.Fprintf(&out, "%s: %v\n", name, value)
+			continue
+		}
+		fmt.Fprintf(&out, "%s: %s\n", name, data)
+	}
+	return out.String()
+}
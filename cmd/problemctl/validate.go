@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"tjdavis.dev/problems"
+)
+
+// finding is a single result of validating a problem document
+type finding struct {
+	// Error is false for findings that are recommendations of the RFC
+	Error   bool
+	Message string
+}
+
+func (f finding) String() string {
+	if f.Error {
+		return "error: " + f.Message
+	}
+	return "warning: " + f.Message
+}
+
+func validate(args []string, stdin io.Reader, stdout io.Writer) (bool, error) {
+	data, err := readInput(args, stdin)
+	if err != nil {
+		return false, err
+	}
+	findings := check(data, problems.DefaultRegistry)
+	ok := true
+	for _, f := range findings {
+		fmt.Fprintln(stdout, f)
+		if f.Error {
+			ok = false
+		}
+	}
+	if ok {
+		fmt.Fprintln(stdout, "ok")
+	}
+	return ok, nil
+}
+
+var memberTypes = map[string]string{
+	"type":     "string",
+	"title":    "string",
+	"status":   "number",
+	"detail":   "string",
+	"instance": "string",
+}
+
+// check validates a JSON problem document against RFC7807 and the types
+// in the registry
+func check(data []byte, reg *problems.Registry) []finding {
+	var findings []finding
+	fail := func(format string, args ...interface{}) {
+		findings = append(findings, finding{Error: true, Message: fmt.Sprintf(format, args...)})
+	}
+	warn := func(format string, args ...interface{}) {
+		findings = append(findings, finding{Message: fmt.Sprintf(format, args...)})
+	}
+
+	var members map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&members); err != nil {
+		fail("the document is not a JSON object: %v", err)
+		return findings
+	}
+
+	for name, want := range memberTypes {
+		value, ok := members[name]
+		if !ok {
+			continue
+		}
+		if got := jsonType(value); got != want {
+			fail("%s must be a %s, not a %s", name, want, got)
+			delete(members, name)
+		}
+	}
+
+	typeURI, _ := members["type"].(string)
+	if _, ok := members["type"]; !ok {
+		typeURI = "about:blank"
+		warn("type is missing and is assumed to be about:blank")
+	} else if _, err := url.Parse(typeURI); err != nil || typeURI == "" {
+		fail("type %q is not a URI reference", typeURI)
+	}
+	if instance, ok := members["instance"].(string); ok {
+		if _, err := url.Parse(instance); err != nil {
+			fail("instance %q is not a URI reference", instance)
+		}
+	}
+
+	status := 0
+	if number, ok := members["status"].(json.Number); ok {
+		n, err := number.Int64()
+		if err != nil || n < 100 || n > 599 {
+			fail("status %s is not an HTTP status code", number)
+		} else {
+			status = int(n)
+			if status < 400 {
+				warn("status %d is not an error status", status)
+			}
+		}
+	} else {
+		warn("status is missing")
+	}
+	title, hasTitle := members["title"].(string)
+	if !hasTitle {
+		warn("title is missing")
+	}
+
+	var extensions []string
+	for name := range members {
+		if _, ok := memberTypes[name]; !ok {
+			extensions = append(extensions, name)
+		}
+	}
+	sort.Strings(extensions)
+
+	if typeURI == "about:blank" {
+		if hasTitle && status != 0 && title != http.StatusText(status) {
+			warn("title %q should be %q when type is about:blank", title, http.StatusText(status))
+		}
+		for _, name := range extensions {
+			fail("extension member %s requires a type other than about:blank", name)
+		}
+		return findings
+	}
+
+	info, ok := reg.Lookup(typeURI)
+	if !ok {
+		warn("type %s is not in the problem type catalog", typeURI)
+		return findings
+	}
+	if status != 0 && info.Status != 0 && status != info.Status {
+		fail("status %d doesn't match the catalog status %d for %s", status, info.Status, typeURI)
+	}
+	if hasTitle && !titleMatches(info, title) {
+		warn("title %q doesn't match the catalog title %q", title, info.Title)
+	}
+	documented := make(map[string]bool)
+	for _, ext := range info.Extensions {
+		documented[strings.ToLower(ext.Name)] = true
+	}
+	for _, name := range extensions {
+		if !documented[strings.ToLower(name)] {
+			warn("extension member %s is not documented for %s", name, typeURI)
+		}
+	}
+	return findings
+}
+
+func titleMatches(info problems.TypeInfo, title string) bool {
+	if title == info.Title {
+		return true
+	}
+	for _, localized := range info.Titles {
+		if title == localized {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case nil:
+		return "null"
+	default:
+		return "object"
+	}
+}
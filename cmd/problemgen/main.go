@@ -0,0 +1,52 @@
+// Command problemgen generates Go type constants, typed problem structs and
+// constructors from a problem OpenAPI document in JSON form.  It is
+// intended to be run from a go:generate directive:
+//
+//	//go:generate go run tjdavis.dev/problems/cmd/problemgen -package billing -o problems_gen.go problems.json
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	"tjdavis.dev/problems/openapi"
+)
+
+func main() {
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "the name of the generated `package`")
+	output := flag.String("o", "", "write the code to `file` instead of stdout")
+	flag.Parse()
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: problemgen [-package name] [-o file] spec.json")
+		os.Exit(2)
+	}
+
+	f, err := os.Open(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	doc, err := openapi.ReadDocument(f)
+	f.Close()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	gen := openapi.CodeGenerator{Package: *pkg, Command: "problemgen"}
+	var out bytes.Buffer
+	if err := gen.Generate(&out, doc); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if *output == "" {
+		_, _ = out.WriteTo(os.Stdout)
+		return
+	}
+	if err := os.WriteFile(*output, out.Bytes(), 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
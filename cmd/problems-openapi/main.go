@@ -0,0 +1,51 @@
+// Command problems-openapi writes the OpenAPI components for the standard
+// problem types and any additional type files.
+//
+//	problems-openapi [-o file] [-title title] [-version version] [types.json ...]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"tjdavis.dev/problems"
+	"tjdavis.dev/problems/openapi"
+	"tjdavis.dev/problems/standard"
+)
+
+func main() {
+	output := flag.String("o", "", "write the document to `file` instead of stdout")
+	title := flag.String("title", "", "the title of the document")
+	version := flag.String("version", "", "the version of the document (default: the type file version)")
+	flag.Parse()
+
+	for _, path := range flag.Args() {
+		if err := problems.DefaultRegistry.LoadFile(path); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	gen := openapi.Generator{
+		Title:       *title,
+		Version:     *version,
+		Schemas:     standard.Schemas,
+		TypeSchemas: standard.SchemaTypes,
+	}
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := gen.Write(w); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
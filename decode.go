@@ -0,0 +1,516 @@
+package problems
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxBodyBytes is the largest request body DecodeJSON reads unless
+// the MaxBodyBytes option is given
+const DefaultMaxBodyBytes int64 = 1 << 20
+
+// Validator checks a decoded request body.  A problem with issues, such as
+// an input validation problem, has its issues merged with those found by
+// the decoder; any other error is returned as is.
+type Validator func(r *http.Request, dst interface{}) error
+
+// DefaultValidator is run by DecodeJSON unless the WithValidator option is
+// given.  Importing the standard package sets it to validate structs with
+// its DefaultValidation.
+var DefaultValidator Validator
+
+type decodeOptions struct {
+	maxBytes     int64
+	disallow     bool
+	validator    Validator
+	validatorSet bool
+}
+
+// DecodeOption configures DecodeJSON
+type DecodeOption func(*decodeOptions)
+
+// MaxBodyBytes limits the size of the request body; larger bodies are a
+// 413 problem
+func MaxBodyBytes(n int64) DecodeOption {
+	return func(opts *decodeOptions) {
+		opts.maxBytes = n
+	}
+}
+
+// DisallowUnknownFields reports each object member that doesn't match a
+// field of the destination as an unknown parameter issue
+func DisallowUnknownFields() DecodeOption {
+	return func(opts *decodeOptions) {
+		opts.disallow = true
+	}
+}
+
+// WithValidator validates the decoded body with validate in place of the
+// DefaultValidator.  A nil validate disables validation.
+func WithValidator(validate Validator) DecodeOption {
+	return func(opts *decodeOptions) {
+		opts.validator = validate
+		opts.validatorSet = true
+	}
+}
+
+// DecodeJSON decodes the JSON request body into dst and validates it.
+// Every failure is returned as a problem ready to render:
+//
+//   - 415 when the Content-Type isn't application/json or a +json type
+//   - 413 when the body is larger than the MaxBodyBytes
+//   - 400 for an empty body, a syntax error, with its line and column, or
+//     data after the JSON value
+//   - 400 input validation problem with an issue for each value of the
+//     wrong type, each unknown field when DisallowUnknownFields is given,
+//     and each issue of the validator.  Issues locate the value with
+//     a JSON Pointer.
+//
+// w is the response writer of the handler, so the server closes the
+// connection after a body over the limit rather than reading the rest.
+func DecodeJSON(w http.ResponseWriter, r *http.Request, dst interface{}, opts ...DecodeOption) error {
+	options := decodeOptions{maxBytes: DefaultMaxBodyBytes}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if !options.validatorSet {
+		options.validator = DefaultValidator
+	}
+
+	if prob := checkMediaType(r.Header.Get("Content-Type")); prob != nil {
+		return prob
+	}
+	if r.Body == nil {
+		return bodyProblem(DetailBodyEmpty, nil)
+	}
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, options.maxBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return tooLargeProblem(tooLarge.Limit)
+		}
+		return FromError(err)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return bodyProblem(DetailBodyEmpty, nil)
+	}
+
+	var issues []Issue
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if err := decoder.Decode(dst); err != nil {
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		var invalidErr *json.InvalidUnmarshalError
+		switch {
+		case errors.As(err, &syntaxErr):
+			// the offset is after the invalid character
+			return syntaxProblem(data, syntaxErr.Offset-1, syntaxErr.Error())
+		case errors.Is(err, io.ErrUnexpectedEOF):
+			return syntaxProblem(data, int64(len(data)), "unexpected end of JSON input")
+		case errors.As(err, &typeErr):
+			issue := typeMismatch(data, typeErr)
+			if field, ok := fieldAt(reflect.TypeOf(dst), issue.Pointer); ok && IsSensitiveField(field) {
+				issue.Sensitive = true
+			}
+			issues = append(issues, issue)
+		case errors.As(err, &invalidErr):
+			return FromError(err)
+		default:
+			// an UnmarshalJSON method of dst rejected the input
+			return bodyProblem("", map[string]interface{}{"error": err.Error()})
+		}
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return bodyProblem(DetailBodyTrailingData, nil)
+	}
+
+	if options.disallow {
+		var value interface{}
+		valueDecoder := json.NewDecoder(bytes.NewReader(data))
+		valueDecoder.UseNumber()
+		if err := valueDecoder.Decode(&value); err == nil {
+			for _, pointer := range unknownFields(reflect.TypeOf(dst), value, "") {
+				issues = append(issues, unknownField(pointer))
+			}
+		}
+	}
+
+	if options.validator != nil {
+		err := options.validator(r, dst)
+		if prob, ok := err.(*Problem); ok && prob.Issues() != nil {
+			issues = mergeIssues(issues, prob.Issues())
+		} else if err != nil {
+			return err
+		}
+	}
+	if len(issues) == 0 {
+		return nil
+	}
+	SortIssues(issues)
+	return InputValidation(issues...)
+}
+
+// checkMediaType returns a 415 problem unless the media type is JSON
+func checkMediaType(contentType string) *Problem {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")) {
+		return nil
+	}
+	prob := New(http.StatusUnsupportedMediaType, "")
+	_ = prob.Set("Type", TypeUnsupportedMediaType)
+	prob.Title = http.StatusText(http.StatusUnsupportedMediaType)
+	prob.SetDetailMessage(DetailUnsupportedMediaType,
+		map[string]interface{}{"mediaType": contentType, "supported": "application/json"})
+	_ = prob.Set("supportedMediaTypes", []string{"application/json"})
+	prob.Header().Set("Accept", "application/json")
+	return prob
+}
+
+// tooLargeProblem reports a body over the limit, in bytes
+func tooLargeProblem(limit int64) *Problem {
+	prob := New(http.StatusRequestEntityTooLarge, "")
+	_ = prob.Set("Type", TypePayloadTooLarge)
+	prob.Title = http.StatusText(http.StatusRequestEntityTooLarge)
+	prob.SetDetailMessage(DetailBodyTooLarge, map[string]interface{}{"limit": limit})
+	_ = prob.Set("limit", limit)
+	return prob
+}
+
+// bodyProblem creates a bad request problem about the body as a whole.  An
+// empty key uses the error parameter as the detail.
+func bodyProblem(key string, params map[string]interface{}) *Problem {
+	prob := New(http.StatusBadRequest, "")
+	_ = prob.Set("Type", TypeBadRequest)
+	_ = prob.Set("Title", "Bad Request")
+	if key == "" {
+		prob.Detail = fmt.Sprint(params["error"])
+		return prob
+	}
+	prob.SetDetailMessage(key, params)
+	for name, value := range params {
+		_ = prob.Set(name, value)
+	}
+	return prob
+}
+
+// syntaxProblem reports a syntax error at the byte offset with its line
+// and column, both starting at 1
+func syntaxProblem(data []byte, offset int64, message string) *Problem {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	} else if offset < 0 {
+		offset = 0
+	}
+	line := 1 + bytes.Count(data[:offset], []byte("\n"))
+	column := int(offset) - bytes.LastIndexByte(data[:offset], '\n')
+	return bodyProblem(DetailBodySyntax, map[string]interface{}{
+		"line":   line,
+		"column": column,
+		"error":  strings.TrimPrefix(message, "json: "),
+	})
+}
+
+// typeMismatch creates the schema violation issue of a value of the wrong
+// JSON type
+func typeMismatch(data []byte, err *json.UnmarshalTypeError) Issue {
+	pointer, value := pointerAt(data, err.Offset)
+	issue := Issue{
+		Type:    TypeSchemaViolation,
+		In:      "body",
+		Name:    pointerName(pointer),
+		Pointer: pointer,
+		Value:   value,
+	}
+	issue.SetDetailMessage(DetailTypeMismatch, map[string]interface{}{
+		"name":  issue.Name,
+		"type":  jsonKind(err.Type),
+		"value": err.Value,
+	})
+	return issue
+}
+
+// unknownField creates the unknown parameter issue of an object member
+func unknownField(pointer string) Issue {
+	issue := Issue{
+		Type:    TypeUnknownParameter,
+		In:      "body",
+		Name:    pointerName(pointer),
+		Pointer: pointer,
+	}
+	issue.SetDetailMessage(DetailUnknownField, map[string]interface{}{"name": issue.Name})
+	return issue
+}
+
+// mergeIssues adds the validator's issues, except those of values already
+// reported by the decoder
+func mergeIssues(issues, more []Issue) []Issue {
+	reported := make(map[string]bool)
+	for _, issue := range issues {
+		reported[issue.Pointer] = true
+	}
+	for _, issue := range more {
+		if issue.Pointer == "" || !reported[issue.Pointer] {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// jsonKind describes the JSON value a Go type decodes from
+func jsonKind(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "an integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a non-negative integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	default:
+		return t.String()
+	}
+}
+
+// pointerName converts a JSON Pointer to a dotted name, eg. /items/3/sku
+// becomes items[3].sku
+func pointerName(pointer string) string {
+	var name strings.Builder
+	for _, token := range strings.Split(pointer, "/")[1:] {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		if _, err := strconv.Atoi(token); err == nil {
+			name.WriteString("[" + token + "]")
+			continue
+		}
+		if name.Len() > 0 {
+			name.WriteString(".")
+		}
+		name.WriteString(token)
+	}
+	return name.String()
+}
+
+func escapeToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// pointerAt returns the JSON Pointer of the value that ends at the byte
+// offset of the document, as reported by json.UnmarshalTypeError, and the
+// value when it isn't an object or array
+func pointerAt(data []byte, offset int64) (string, interface{}) {
+	type frame struct {
+		array bool
+		index int
+		key   string
+	}
+	var stack []frame
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	expectKey := false
+	pointer := func() string {
+		var p strings.Builder
+		for _, f := range stack {
+			p.WriteString("/")
+			if f.array {
+				p.WriteString(strconv.Itoa(f.index))
+			} else {
+				p.WriteString(escapeToken(f.key))
+			}
+		}
+		return p.String()
+	}
+	// value is called after each complete value of the current container
+	value := func() {
+		if len(stack) == 0 {
+			return
+		}
+		top := &stack[len(stack)-1]
+		if top.array {
+			top.index++
+		} else {
+			expectKey = true
+		}
+	}
+	for {
+		start := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err != nil {
+			return pointer(), nil
+		}
+		if len(stack) > 0 {
+			top := stack[len(stack)-1]
+			if !top.array && expectKey {
+				if key, ok := token.(string); ok {
+					stack[len(stack)-1].key = key
+					expectKey = false
+					continue
+				}
+			}
+		}
+		current := pointer()
+		switch token {
+		case json.Delim('{'):
+			if decoder.InputOffset() >= offset {
+				return current, nil
+			}
+			stack = append(stack, frame{})
+			expectKey = true
+			continue
+		case json.Delim('['):
+			if decoder.InputOffset() >= offset {
+				return current, nil
+			}
+			stack = append(stack, frame{array: true})
+			continue
+		case json.Delim('}'), json.Delim(']'):
+			stack = stack[:len(stack)-1]
+			current = pointer()
+			if decoder.InputOffset() >= offset {
+				return current, nil
+			}
+			value()
+			continue
+		}
+		if start < offset && decoder.InputOffset() >= offset {
+			return current, token
+		}
+		value()
+	}
+}
+
+// unknownFields returns the JSON Pointers of the object members of value
+// that don't match a field of the struct type t, matching names without
+// regard to case as encoding/json does
+func unknownFields(t reflect.Type, value interface{}, pointer string) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if reflect.PtrTo(t).Implements(jsonUnmarshaler) {
+		return nil
+	}
+	var unknown []string
+	switch v := value.(type) {
+	case map[string]interface{}:
+		switch t.Kind() {
+		case reflect.Struct:
+			fields := jsonFields(t)
+			keys := sortedKeys(v)
+			for _, key := range keys {
+				field, ok := fields[key]
+				if !ok {
+					field, ok = fields[strings.ToLower(key)]
+				}
+				if !ok {
+					unknown = append(unknown, pointer+"/"+escapeToken(key))
+					continue
+				}
+				unknown = append(unknown, unknownFields(field.Type, v[key], pointer+"/"+escapeToken(key))...)
+			}
+		case reflect.Map:
+			for _, key := range sortedKeys(v) {
+				unknown = append(unknown, unknownFields(t.Elem(), v[key], pointer+"/"+escapeToken(key))...)
+			}
+		}
+	case []interface{}:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, item := range v {
+				unknown = append(unknown, unknownFields(t.Elem(), item, pointer+"/"+strconv.Itoa(i))...)
+			}
+		}
+	}
+	return unknown
+}
+
+// fieldAt returns the struct field a JSON Pointer refers to
+func fieldAt(t reflect.Type, pointer string) (reflect.StructField, bool) {
+	var field reflect.StructField
+	found := false
+	for _, token := range strings.Split(pointer, "/")[1:] {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		switch t.Kind() {
+		case reflect.Struct:
+			token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+			fields := jsonFields(t)
+			f, ok := fields[token]
+			if !ok {
+				f, ok = fields[strings.ToLower(token)]
+			}
+			if !ok {
+				return field, false
+			}
+			field, found, t = f, true, f.Type
+		case reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		default:
+			return field, false
+		}
+	}
+	return field, found
+}
+
+var jsonUnmarshaler = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// jsonFields maps the JSON names of the fields of a struct, and their
+// lower case forms, to the fields.  The fields of untagged embedded
+// structs are promoted.
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if name == "-" && !strings.HasPrefix(tag, "-,") {
+			continue
+		}
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			for embedded, embeddedField := range jsonFields(fieldType) {
+				if _, ok := fields[embedded]; !ok {
+					fields[embedded] = embeddedField
+				}
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field
+		fields[strings.ToLower(name)] = field
+	}
+	return fields
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
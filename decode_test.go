@@ -0,0 +1,151 @@
+package problems
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type testLine struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+type testAddress struct {
+	City string `json:"city"`
+}
+
+type testOrder struct {
+	testAddress
+	Customer string     `json:"customer"`
+	Lines    []testLine `json:"lines"`
+}
+
+func jsonRequest(body string) *http.Request {
+	r := httptest.NewRequest("POST", "/orders", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json; charset=utf-8")
+	return r
+}
+
+func TestDecodeJSON(t *testing.T) {
+	var order testOrder
+	err := DecodeJSON(httptest.NewRecorder(), jsonRequest(`{"customer": "acme", "City": "Paris", "lines": [{"sku": "a", "quantity": 2}]}`), &order,
+		DisallowUnknownFields(), WithValidator(nil))
+	if err != nil {
+		t.Fatalf("DecodeJSON() error = %v", err)
+	}
+	if order.Customer != "acme" || order.City != "Paris" || len(order.Lines) != 1 || order.Lines[0].Quantity != 2 {
+		t.Errorf("DecodeJSON() order = %+v", order)
+	}
+}
+
+func TestDecodeJSON_Problems(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		opts        []DecodeOption
+		status      int
+		detail      string
+	}{
+		{"Test media type", "text/plain", `{}`, nil, 415,
+			"The media type text/plain is not supported; use application/json"},
+		{"Test problem media type", "application/problem+json", `{}`, nil, 0, ""},
+		{"Test too large", "application/json", `{"customer": "acme"}`, []DecodeOption{MaxBodyBytes(8)}, 413,
+			"The request body is larger than 8 bytes"},
+		{"Test empty", "application/json", " \n", nil, 400, "The request body is empty"},
+		{"Test syntax", "application/json", "{\n  \"customer\": \"acme\",\n  \"lines\": [}\n}", nil, 400,
+			"The request body is not valid JSON at line 3, column 13: invalid character '}' looking for beginning of value"},
+		{"Test truncated", "application/json", `{"customer": "acme"`, nil, 400,
+			"The request body is not valid JSON at line 1, column 20: unexpected end of JSON input"},
+		{"Test trailing data", "application/json", `{"customer": "acme"} {}`, nil, 400,
+			"The request body must contain a single JSON value"},
+		{"Test type mismatch", "application/json", `{"lines": [{"sku": "a"}, {"quantity": "2"}]}`, nil, 400,
+			"lines[1].quantity must be an integer, not string"},
+		{"Test unknown fields allowed", "application/json", `{"note": "x"}`, nil, 0, ""},
+		{"Test unknown field", "application/json", `{"lines": [{"sku": "a", "price": 2}]}`,
+			[]DecodeOption{DisallowUnknownFields()}, 400, "lines[0].price is not a known field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := jsonRequest(tt.body)
+			r.Header.Set("Content-Type", tt.contentType)
+			var order testOrder
+			err := DecodeJSON(httptest.NewRecorder(), r, &order, append(tt.opts, WithValidator(nil))...)
+			if tt.status == 0 {
+				if err != nil {
+					t.Errorf("DecodeJSON() error = %v", err)
+				}
+				return
+			}
+			prob, ok := err.(*Problem)
+			if !ok {
+				t.Fatalf("DecodeJSON() error = %v, want a problem", err)
+			}
+			if prob.Status != tt.status || prob.Detail != tt.detail {
+				t.Errorf("DecodeJSON() = %d %q, want %d %q", prob.Status, prob.Detail, tt.status, tt.detail)
+			}
+			if prob.Type == "" || prob.Type == "about:blank" {
+				t.Errorf("DecodeJSON() type = %q, want a problem type", prob.Type)
+			}
+		})
+	}
+}
+
+func TestDecodeJSON_Issues(t *testing.T) {
+	validate := func(r *http.Request, dst interface{}) error {
+		order := dst.(*testOrder)
+		var issues []Issue
+		for _, pointer := range []string{"/customer", "/lines/1/quantity"} {
+			issues = append(issues, Issue{Type: TypeSchemaViolation, In: "body", Pointer: pointer})
+		}
+		if order.Customer == "" {
+			return InputValidation(issues...)
+		}
+		return nil
+	}
+	body := `{"lines": [{"sku": "a", "colour": "red"}, {"quantity": true}], "Extra": 1}`
+	err := DecodeJSON(httptest.NewRecorder(), jsonRequest(body), &testOrder{}, DisallowUnknownFields(), WithValidator(validate))
+	prob, ok := err.(*Problem)
+	if !ok {
+		t.Fatalf("DecodeJSON() error = %v, want a problem", err)
+	}
+	want := []struct{ typeURI, pointer string }{
+		{TypeUnknownParameter, "/Extra"},
+		{TypeSchemaViolation, "/customer"},
+		{TypeUnknownParameter, "/lines/0/colour"},
+		{TypeSchemaViolation, "/lines/1/quantity"},
+	}
+	issues := prob.Issues()
+	if len(issues) != len(want) {
+		t.Fatalf("DecodeJSON() issues = %v, want %d", issues, len(want))
+	}
+	for i, issue := range issues {
+		if issue.Type != want[i].typeURI || issue.Pointer != want[i].pointer {
+			t.Errorf("DecodeJSON() issue %d = %v %v", i, issue.Type, issue.Pointer)
+		}
+	}
+	if issues[3].Value != true {
+		t.Errorf("DecodeJSON() issue value = %v", issues[3].Value)
+	}
+}
+
+func TestPointerAt(t *testing.T) {
+	data := []byte(`{"a": {"b": [1, {"c/d": "x"}, [true]]}, "e": null}`)
+	tests := []struct {
+		value   string
+		pointer string
+	}{
+		{`1`, "/a/b/0"},
+		{`"x"`, "/a/b/1/c~1d"},
+		{`true`, "/a/b/2/0"},
+		{`null`, "/e"},
+	}
+	for _, tt := range tests {
+		offset := int64(strings.Index(string(data), tt.value) + len(tt.value))
+		if got, _ := pointerAt(data, offset); got != tt.pointer {
+			t.Errorf("pointerAt(%s) = %v, want %v", tt.value, got, tt.pointer)
+		}
+	}
+}
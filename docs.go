@@ -0,0 +1,195 @@
+package problems
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// DefaultTypePrefix is the URN prefix used by the standard problem types
+const DefaultTypePrefix = "urn:problem-type:"
+
+// Resolver maps problem type URNs to dereferenceable documentation URLs.
+// A type of `urn:problem-type:input-validation:schemaViolation` resolves
+// to `{BaseURL}/input-validation/schemaViolation`.
+type Resolver struct {
+	// BaseURL is the absolute URL the documentation is served from
+	BaseURL string
+	// Prefix is the URN prefix that is replaced by BaseURL
+	Prefix string
+}
+
+// NewResolver creates a resolver for the standard `urn:problem-type:` prefix
+func NewResolver(baseURL string) *Resolver {
+	return &Resolver{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		Prefix:  DefaultTypePrefix,
+	}
+}
+
+// Resolve returns the documentation URL for the problem type.  Types that
+// don't use the resolver's prefix are returned unchanged.
+func (res *Resolver) Resolve(typeURI string) string {
+	name := res.Name(typeURI)
+	if name == "" {
+		return typeURI
+	}
+	return res.BaseURL + "/" + name
+}
+
+// Name returns the path of the problem type relative to BaseURL or an
+// empty string if the type doesn't use the resolver's prefix.
+func (res *Resolver) Name(typeURI string) string {
+	if !strings.HasPrefix(typeURI, res.Prefix) || len(typeURI) == len(res.Prefix) {
+		return ""
+	}
+	return strings.ReplaceAll(strings.TrimPrefix(typeURI, res.Prefix), ":", "/")
+}
+
+// TypeFor is the reverse of Name and returns the type URI for a relative path
+func (res *Resolver) TypeFor(name string) string {
+	return res.Prefix + strings.ReplaceAll(strings.Trim(name, "/"), "/", ":")
+}
+
+// DocHandler serves human-readable documentation for the problem types
+// in a registry.  The root path lists every type and each type is served
+// at the path returned by Resolver.Name.  Mount it with http.StripPrefix
+// when it is not served from the root of the server; without a BaseURL the
+// index links to the types relative to the requested URL, so they keep the
+// prefix.  A nil Registry uses the DefaultRegistry.
+type DocHandler struct {
+	Registry *Registry
+	Resolver *Resolver
+}
+
+// NewDocHandler creates a documentation handler.  A nil registry uses the
+// DefaultRegistry and a nil resolver the standard prefix with no base URL.
+func NewDocHandler(reg *Registry, res *Resolver) *DocHandler {
+	if reg == nil {
+		reg = DefaultRegistry
+	}
+	if res == nil {
+		res = NewResolver("")
+	}
+	return &DocHandler{Registry: reg, Resolver: res}
+}
+
+// resolver returns the Resolver, or the default of NewDocHandler when a
+// handler was created without one
+func (h *DocHandler) resolver() *Resolver {
+	if h.Resolver == nil {
+		return NewResolver("")
+	}
+	return h.Resolver
+}
+
+// registry returns the Registry, or the DefaultRegistry when a handler was
+// created without one
+func (h *DocHandler) registry() *Registry {
+	if h.Registry == nil {
+		return DefaultRegistry
+	}
+	return h.Registry
+}
+
+type docPage struct {
+	Info    TypeInfo
+	Href    string
+	Example string
+}
+
+// page returns the page of the type; base is the path the links are
+// relative to when the resolver has no BaseURL
+func (h *DocHandler) page(info TypeInfo, base string) docPage {
+	example, _ := json.MarshalIndent(info.Example(), "", "  ")
+	href := info.Href
+	if href == "" {
+		res := h.resolver()
+		if name := res.Name(info.Type); res.BaseURL == "" && name != "" {
+			href = base + "/" + name
+		} else {
+			href = res.Resolve(info.Type)
+		}
+	}
+	return docPage{Info: info, Href: href, Example: string(example)}
+}
+
+// relativeBase returns the path the links of the index are relative to.
+// It uses the URL requested by the client, which http.StripPrefix doesn't
+// change, so an index at /docs links to docs/name and one at /docs/ to
+// ./name.
+func relativeBase(r *http.Request) string {
+	requested := r.URL.Path
+	if u, err := url.ParseRequestURI(r.RequestURI); err == nil {
+		requested = u.Path
+	}
+	if requested == "" || strings.HasSuffix(requested, "/") {
+		return "."
+	}
+	return path.Base(requested)
+}
+
+// ServeHTTP renders the index or the documentation page for a single type
+func (h *DocHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		prob := New(http.StatusMethodNotAllowed, "Problem documentation is read-only")
+		prob.Header().Set("Allow", "GET, HEAD")
+		_ = prob.Render(w, r)
+		return
+	}
+	name := strings.Trim(r.URL.Path, "/")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if name == "" {
+		var pages []docPage
+		base := relativeBase(r)
+		for _, info := range h.registry().Types() {
+			pages = append(pages, h.page(info, base))
+		}
+		_ = indexTemplate.Execute(w, pages)
+		return
+	}
+	info, ok := h.registry().Lookup(h.resolver().TypeFor(name))
+	if !ok {
+		w.Header().Del("Content-Type")
+		_ = New(http.StatusNotFound, "No problem type is documented at "+r.URL.Path).Render(w, r)
+		return
+	}
+	_ = typeTemplate.Execute(w, h.page(info, ""))
+}
+
+var indexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Problem Types</title></head>
+<body>
+<h1>Problem Types</h1>
+<table>
+<tr><th>Type</th><th>Status</th><th>Title</th></tr>
+{{range .}}<tr><td><a href="{{.Href}}">{{.Info.Type}}</a></td><td>{{.Info.Status}}</td><td>{{.Info.Title}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+var typeTemplate = template.Must(template.New("type").Parse(`<!DOCTYPE html>
+<html>
+<head><title>{{.Info.Title}}</title></head>
+<body>
+<h1>{{.Info.Title}}</h1>
+<dl>
+<dt>Type</dt><dd><code>{{.Info.Type}}</code></dd>
+{{if .Info.Status}}<dt>Status</dt><dd>{{.Info.Status}}</dd>{{end}}
+</dl>
+{{if .Info.Description}}<p>{{.Info.Description}}</p>{{end}}
+{{if .Info.Extensions}}<h2>Extension Members</h2>
+<table>
+<tr><th>Name</th><th>Type</th><th>Description</th></tr>
+{{range .Info.Extensions}}<tr><td><code>{{.Name}}</code></td><td>{{.Type}}</td><td>{{.Description}}</td></tr>
+{{end}}</table>{{end}}
+<h2>Example</h2>
+<pre>{{.Example}}</pre>
+</body>
+</html>
+`))
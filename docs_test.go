@@ -0,0 +1,156 @@
+package problems
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestResolver_Resolve(t *testing.T) {
+	res := NewResolver("https://api.example.com/problems/")
+	tests := []struct {
+		name    string
+		typeURI string
+		want    string
+	}{
+		{
+			name:    "Test simple type",
+			typeURI: "urn:problem-type:noAccessToken",
+			want:    "https://api.example.com/problems/noAccessToken",
+		},
+		{
+			name:    "Test nested type",
+			typeURI: "urn:problem-type:input-validation:schemaViolation",
+			want:    "https://api.example.com/problems/input-validation/schemaViolation",
+		},
+		{
+			name:    "Test foreign type",
+			typeURI: "https://example.com/problems/other",
+			want:    "https://example.com/problems/other",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := res.Resolve(tt.typeURI)
+			if got != tt.want {
+				t.Errorf("Resolver.Resolve() = %v, want %v", got, tt.want)
+			}
+			if name := res.Name(tt.typeURI); name != "" && res.TypeFor(name) != tt.typeURI {
+				t.Errorf("Resolver.TypeFor() = %v, want %v", res.TypeFor(name), tt.typeURI)
+			}
+		})
+	}
+}
+
+func TestDocHandler(t *testing.T) {
+	reg := NewRegistry()
+	_ = reg.Register(TypeInfo{
+		Type:   "urn:problem-type:example:test",
+		Title:  "Test Problem",
+		Status: 409,
+		Extensions: []Extension{
+			{Name: "traceId", Type: "string", Example: "12345"},
+		},
+	})
+	handler := NewDocHandler(reg, NewResolver("https://api.example.com/problems"))
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "Test index",
+			path:       "/",
+			wantStatus: 200,
+			wantBody:   "https://api.example.com/problems/example/test",
+		},
+		{
+			name:       "Test type page",
+			path:       "/example/test",
+			wantStatus: 200,
+			wantBody:   "&#34;traceid&#34;: &#34;12345&#34;",
+		},
+		{
+			name:       "Test unknown type",
+			path:       "/example/missing",
+			wantStatus: 404,
+			wantBody:   "No problem type is documented",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.wantStatus {
+				t.Errorf("DocHandler status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("DocHandler body = %v, want %v", w.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestDocHandler_NilResolver(t *testing.T) {
+	reg := NewRegistry()
+	_ = reg.Register(TypeInfo{Type: "urn:problem-type:example:test", Title: "Test Problem", Status: 409})
+	for _, handler := range []*DocHandler{NewDocHandler(reg, nil), {Registry: reg}} {
+		for _, path := range []string{"/", "/example/test"} {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+			if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "Test Problem") {
+				t.Errorf("DocHandler %s = %d %v", path, w.Code, w.Body.String())
+			}
+		}
+	}
+}
+
+func TestDocHandler_StripPrefix(t *testing.T) {
+	reg := NewRegistry()
+	_ = reg.Register(TypeInfo{Type: "urn:problem-type:example:test", Title: "Test Problem", Status: 409})
+	mux := http.NewServeMux()
+	mux.Handle("/docs/", http.StripPrefix("/docs", NewDocHandler(reg, nil)))
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/docs/", `href="./example/test"`},
+		{"/docs", `href="docs/example/test"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler := http.Handler(mux)
+			if tt.path == "/docs" {
+				// the mux would redirect to /docs/
+				handler = http.StripPrefix("/docs", NewDocHandler(reg, nil))
+			}
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("DocHandler %s = %v, want %v", tt.path, w.Body.String(), tt.want)
+			}
+		})
+	}
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs/example/test", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "Test Problem") {
+		t.Errorf("DocHandler type page = %d %v", w.Code, w.Body.String())
+	}
+}
+
+func TestDocHandler_NilRegistry(t *testing.T) {
+	defer func(reg *Registry) { DefaultRegistry = reg }(DefaultRegistry)
+	DefaultRegistry = NewRegistry()
+	_ = DefaultRegistry.Register(TypeInfo{Type: "urn:problem-type:example:default", Title: "Default Problem", Status: 400})
+	for _, handler := range []*DocHandler{NewDocHandler(nil, nil), {}} {
+		for _, path := range []string{"/", "/example/default"} {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+			if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "Default Problem") {
+				t.Errorf("DocHandler %s = %d %v", path, w.Code, w.Body.String())
+			}
+		}
+	}
+}
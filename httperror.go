@@ -0,0 +1,56 @@
+package problems
+
+import (
+	"net/http"
+	"strings"
+)
+
+// DetailMethodNotAllowed has the method and allowed parameters.  The
+// English text is registered with the DefaultTranslations.
+const DetailMethodNotAllowed = "http.methodNotAllowed"
+
+func init() {
+	DefaultTranslations.AddDetail(DefaultLocale, DetailMethodNotAllowed, "The method {method} is not allowed; use {allowed}")
+}
+
+// Error replies to the request with a problem of the status and detail,
+// like http.Error.  An empty detail is the text of the status.  The
+// problem has the about:blank type and no instance.  It is written by
+// Render, so the only package configuration that applies is the
+// Accept-Language negotiation with the DefaultTranslations; the package
+// has no setting to fill in the instance or redact the detail.
+func Error(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	if detail == "" {
+		detail = http.StatusText(status)
+	}
+	prob := New(status, detail)
+	prob.Title = http.StatusText(status)
+	writeProblem(w, r, prob)
+}
+
+// NotFound replies to the request with a 404 problem, like http.NotFound
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	prob := New(http.StatusNotFound, "")
+	prob.Title = http.StatusText(http.StatusNotFound)
+	prob.SetDetailMessage(DetailNotFound, nil)
+	writeProblem(w, r, prob)
+}
+
+// MethodNotAllowed replies to the request with a 405 problem and an Allow
+// header listing the allowed methods
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request, allowed ...string) {
+	prob := New(http.StatusMethodNotAllowed, "")
+	prob.Title = http.StatusText(http.StatusMethodNotAllowed)
+	prob.SetDetailMessage(DetailMethodNotAllowed,
+		map[string]interface{}{"method": r.Method, "allowed": strings.Join(allowed, ", ")})
+	prob.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeProblem(w, r, prob)
+}
+
+// writeProblem renders the problem, dropping the headers of a body the
+// handler may have started like http.Error does
+func writeProblem(w http.ResponseWriter, r *http.Request, prob *Problem) {
+	w.Header().Del("Content-Length")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	_ = prob.Render(w, r)
+}
@@ -0,0 +1,55 @@
+package problems
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPHelpers(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		status  int
+		detail  string
+		allow   string
+	}{
+		{"Test error", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Length", "10")
+			Error(w, r, http.StatusBadRequest, "id must be a number")
+		}, http.StatusBadRequest, "id must be a number", ""},
+		{"Test error without detail", func(w http.ResponseWriter, r *http.Request) {
+			Error(w, r, http.StatusConflict, "")
+		}, http.StatusConflict, "Conflict", ""},
+		{"Test not found", NotFound, http.StatusNotFound, "The requested resource cannot be found", ""},
+		{"Test method not allowed", func(w http.ResponseWriter, r *http.Request) {
+			MethodNotAllowed(w, r, "GET", "HEAD")
+		}, http.StatusMethodNotAllowed, "The method DELETE is not allowed; use GET, HEAD", "GET, HEAD"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.handler(w, httptest.NewRequest("DELETE", "/items/x", nil))
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != ProblemMediaType {
+				t.Errorf("Content-Type = %v, want %v", ct, ProblemMediaType)
+			}
+			if cl := w.Header().Get("Content-Length"); cl != "" {
+				t.Errorf("Content-Length = %v, want none", cl)
+			}
+			if allow := w.Header().Get("Allow"); allow != tt.allow {
+				t.Errorf("Allow = %v, want %v", allow, tt.allow)
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body["detail"] != tt.detail || body["title"] != http.StatusText(tt.status) {
+				t.Errorf("body = %v, want detail %q", body, tt.detail)
+			}
+		})
+	}
+}
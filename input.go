@@ -0,0 +1,67 @@
+package problems
+
+import "net/http"
+
+// Input validation problem types.  The standard package documents them
+// with the rest of its problem types.
+const (
+	TypeBadRequest           = "urn:problem-type:badRequest"
+	TypeSchemaViolation      = "urn:problem-type:input-validation:schemaViolation"
+	TypeUnknownParameter     = "urn:problem-type:input-validation:unknownParameter"
+	TypePayloadTooLarge      = "urn:problem-type:payloadTooLarge"
+	TypeUnsupportedMediaType = "urn:problem-type:unsupportedMediaType"
+)
+
+// Detail keys of the input validation problems.  The English text is
+// registered with the DefaultTranslations.
+const (
+	DetailInputValidation = "input.invalid"
+	// DetailBodyEmpty is the detail of a request without a body
+	DetailBodyEmpty = "input.bodyEmpty"
+	// DetailBodySyntax has the line, column and error parameters
+	DetailBodySyntax = "input.bodySyntax"
+	// DetailBodyTrailingData is the detail of a body with more than one value
+	DetailBodyTrailingData = "input.bodyTrailingData"
+	// DetailBodyTooLarge has the limit parameter, in bytes
+	DetailBodyTooLarge = "input.bodyTooLarge"
+	// DetailUnsupportedMediaType has the mediaType and supported parameters
+	DetailUnsupportedMediaType = "input.unsupportedMediaType"
+	// DetailTypeMismatch has the name, type and value parameters
+	DetailTypeMismatch = "input.typeMismatch"
+	// DetailUnknownField has the name parameter
+	DetailUnknownField = "input.unknownField"
+)
+
+func init() {
+	for key, text := range map[string]string{
+		DetailInputValidation:      "The input message is incorrect; see issues for more information",
+		DetailBodyEmpty:            "The request body is empty",
+		DetailBodySyntax:           "The request body is not valid JSON at line {line}, column {column}: {error}",
+		DetailBodyTrailingData:     "The request body must contain a single JSON value",
+		DetailBodyTooLarge:         "The request body is larger than {limit} bytes",
+		DetailUnsupportedMediaType: "The media type {mediaType} is not supported; use {supported}",
+		DetailTypeMismatch:         "{name} must be {type}, not {value}",
+		DetailUnknownField:         "{name} is not a known field",
+	} {
+		DefaultTranslations.AddDetail(DefaultLocale, key, text)
+	}
+}
+
+// InputValidation creates the bad request problem reporting the issues
+// with the input.  Repeated issues are removed and no more than MaxIssues
+// are reported.  A single issue also provides the detail of the problem.
+func InputValidation(issues ...Issue) *Problem {
+	prob := New(http.StatusBadRequest, "")
+	_ = prob.Set("Type", TypeBadRequest)
+	_ = prob.Set("Title", "Bad Request")
+	issues = DedupeIssues(issues)
+	if len(issues) == 1 {
+		prob.Detail = issues[0].Detail
+		prob.detailKey = issues[0].detailKey
+		prob.detailParams = issues[0].detailParams
+	} else {
+		prob.SetDetailMessage(DetailInputValidation, nil)
+	}
+	_ = prob.AddIssues(issues...)
+	return prob
+}
@@ -0,0 +1,291 @@
+package problems
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MaxIssues is the most issues a problem carries.  AddIssues drops the
+// issues over the limit and counts them in the omittedIssues extension
+// member.  Zero or less means no limit.
+var MaxIssues = 100
+
+// Issue is one problem with the input of a request, carried in the issues
+// extension member of an input validation problem.  It is serialized like
+// a problem without a status, with the location of the input:
+//
+//	{
+//	  "type": "urn:problem-type:input-validation:schemaViolation",
+//	  "detail": "quantity must be 1 or greater",
+//	  "in": "body",
+//	  "name": "lines[0].quantity",
+//	  "pointer": "/lines/0/quantity",
+//	  "value": 0
+//	}
+type Issue struct {
+	// Type is a URI reference that identifies the issue type
+	Type string `json:"type,omitempty"`
+	// Title is a short, human-readable summary of the issue type
+	Title string `json:"title,omitempty"`
+	// Detail is a human-readable explanation of this issue
+	Detail string `json:"detail,omitempty"`
+	// In is the location of the input: body, header, path or query
+	In string `json:"in,omitempty"`
+	// Name is the name of the parameter or field in error
+	Name string `json:"name,omitempty"`
+	// Pointer is the RFC 6901 JSON Pointer to the value in a body
+	Pointer string `json:"pointer,omitempty"`
+	// Value is the value that was provided.  The value of a sensitive issue
+	// is replaced with the MaskedValue when the issue is serialized.
+	Value interface{} `json:"value,omitempty"`
+	// Sensitive masks the value; see IsSensitive
+	Sensitive bool `json:"-"`
+	// Extensions are the other members of the issue
+	Extensions   map[string]interface{} `json:"-"`
+	detailKey    string
+	detailParams map[string]interface{}
+}
+
+// issueMembers are the members of an issue with a field
+var issueMembers = []string{"type", "title", "detail", "in", "name", "pointer", "value"}
+
+// SetDetailMessage sets the detail from the message for key, formatted
+// with the named parameters, as Problem.SetDetailMessage does
+func (issue *Issue) SetDetailMessage(key string, params map[string]interface{}) {
+	issue.detailKey = key
+	issue.detailParams = params
+	if detail, ok := DefaultTranslations.Detail(DefaultLocale, key, params); ok {
+		issue.Detail = detail
+	}
+}
+
+// DetailKey returns the key of the detail text
+func (issue Issue) DetailKey() string {
+	return issue.detailKey
+}
+
+// Localize returns a copy of the issue with the title and detail in the
+// requested language.  Titles come from the translations or the Titles of
+// the registered problem type.
+func (issue Issue) Localize(locale string, tr *Translations) Issue {
+	localized, _ := issue.localize(locale, tr)
+	return localized
+}
+
+// localize is Localize, also reporting whether any text was translated
+func (issue Issue) localize(locale string, tr *Translations) (Issue, bool) {
+	if tr == nil {
+		tr = DefaultTranslations
+	}
+	translated := false
+	if title, ok := tr.Title(locale, issue.Type); ok {
+		issue.Title, translated = title, true
+	} else if info, ok := LookupType(issue.Type); ok {
+		if title, ok := info.Titles[normalizeLocale(locale)]; ok {
+			issue.Title, translated = title, true
+		}
+	}
+	if issue.detailKey != "" {
+		if detail, ok := tr.Detail(locale, issue.detailKey, issue.detailParams); ok {
+			issue.Detail, translated = detail, true
+		}
+	}
+	return issue, translated
+}
+
+// MarshalJSON writes the fields and extensions as one object
+func (issue Issue) MarshalJSON() ([]byte, error) {
+	out := make(map[string]interface{}, len(issue.Extensions)+len(issueMembers))
+	for k, v := range issue.Extensions {
+		out[k] = v
+	}
+	for k, v := range map[string]string{
+		"type": issue.Type, "title": issue.Title, "detail": issue.Detail,
+		"in": issue.In, "name": issue.Name, "pointer": issue.Pointer,
+	} {
+		if v != "" {
+			out[k] = v
+		}
+	}
+	if issue.IsSensitive() {
+		if MaskedValue != nil {
+			out["value"] = MaskedValue
+		}
+	} else if issue.Value != nil {
+		out["value"] = issue.Value
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON reads the fields and keeps the other members as extensions
+func (issue *Issue) UnmarshalJSON(data []byte) error {
+	var members map[string]interface{}
+	if err := json.Unmarshal(data, &members); err != nil {
+		return FromError(err)
+	}
+	*issue = Issue{}
+	for k, v := range members {
+		switch k {
+		case "value":
+			issue.Value = v
+		case "type", "title", "detail", "in", "name", "pointer":
+			text, ok := v.(string)
+			if !ok {
+				return Errorf(500, "The issue member %s must be a string, not %v", k, v)
+			}
+			switch k {
+			case "type":
+				issue.Type = text
+			case "title":
+				issue.Title = text
+			case "detail":
+				issue.Detail = text
+			case "in":
+				issue.In = text
+			case "name":
+				issue.Name = text
+			case "pointer":
+				issue.Pointer = text
+			}
+		default:
+			if issue.Extensions == nil {
+				issue.Extensions = make(map[string]interface{})
+			}
+			issue.Extensions[k] = v
+		}
+	}
+	return nil
+}
+
+// key identifies the issue for DedupeIssues
+func (issue Issue) key() string {
+	return strings.Join([]string{issue.Type, issue.In, issue.Name, issue.Pointer, issue.Detail,
+		fmt.Sprintf("%#v", issue.Value)}, "\x00")
+}
+
+// DedupeIssues removes the repeats of an issue with the same type,
+// location, detail and value, keeping the first
+func DedupeIssues(issues []Issue) []Issue {
+	seen := make(map[string]bool, len(issues))
+	out := make([]Issue, 0, len(issues))
+	for _, issue := range issues {
+		if key := issue.key(); !seen[key] {
+			seen[key] = true
+			out = append(out, issue)
+		}
+	}
+	return out
+}
+
+// SortIssues orders the issues by location, then pointer and name, keeping
+// the order of the issues of the same input
+func SortIssues(issues []Issue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.In != b.In {
+			return a.In < b.In
+		}
+		if a.Pointer != b.Pointer {
+			return a.Pointer < b.Pointer
+		}
+		return a.Name < b.Name
+	})
+}
+
+// AddIssues appends the issues to the issues extension member, which
+// requires a Type other than about:blank.  No more than MaxIssues are kept.
+func (prob *Problem) AddIssues(issues ...Issue) error {
+	all := append(prob.Issues(), issues...)
+	omitted := prob.omittedIssues()
+	if MaxIssues > 0 && len(all) > MaxIssues {
+		omitted += len(all) - MaxIssues
+		all = all[:MaxIssues]
+	}
+	if err := prob.Set("issues", all); err != nil {
+		return err
+	}
+	if omitted > 0 {
+		// a decoded problem has the member under its lowercase wire name
+		delete(prob.Attributes, "omittedissues")
+		return prob.Set("omittedIssues", omitted)
+	}
+	return nil
+}
+
+// omittedIssues returns the omittedIssues extension member, whether it was
+// set by AddIssues or decoded, under its lowercase wire name, from JSON
+func (prob *Problem) omittedIssues() int {
+	for _, key := range []string{"omittedIssues", "omittedissues"} {
+		switch n := prob.Attributes[key].(type) {
+		case int:
+			return n
+		case int64:
+			return int(n)
+		case float64:
+			return int(n)
+		case json.Number:
+			if i, err := n.Int64(); err == nil {
+				return int(i)
+			}
+		}
+	}
+	return 0
+}
+
+// Issues returns the issues of the problem, whether they were added with
+// AddIssues, set as problems or decoded from JSON or XML
+func (prob *Problem) Issues() []Issue {
+	value := prob.Get("issues")
+	switch v := value.(type) {
+	case nil, string:
+		return nil
+	case []Issue:
+		return append([]Issue(nil), v...)
+	case []Problem:
+		issues := make([]Issue, 0, len(v))
+		for _, p := range v {
+			issues = append(issues, p.issue())
+		}
+		return issues
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var issues []Issue
+	if err := json.Unmarshal(data, &issues); err != nil {
+		return nil
+	}
+	return issues
+}
+
+// issue converts a problem used as an issue
+func (prob Problem) issue() Issue {
+	issue := Issue{
+		Type:         prob.Type,
+		Title:        prob.Title,
+		Detail:       prob.Detail,
+		detailKey:    prob.detailKey,
+		detailParams: prob.detailParams,
+	}
+	for k, v := range prob.Attributes {
+		switch strings.ToLower(k) {
+		case "in":
+			issue.In = fmt.Sprint(v)
+		case "name":
+			issue.Name = fmt.Sprint(v)
+		case "pointer":
+			issue.Pointer = fmt.Sprint(v)
+		case "value":
+			issue.Value = v
+		default:
+			if issue.Extensions == nil {
+				issue.Extensions = make(map[string]interface{})
+			}
+			issue.Extensions[strings.ToLower(k)] = v
+		}
+	}
+	return issue
+}
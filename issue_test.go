@@ -0,0 +1,145 @@
+package problems
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestIssue_JSON(t *testing.T) {
+	issue := Issue{
+		Type:       TypeSchemaViolation,
+		Detail:     "quantity must be 1 or greater",
+		In:         "body",
+		Name:       "lines[0].quantity",
+		Pointer:    "/lines/0/quantity",
+		Value:      float64(0),
+		Extensions: map[string]interface{}{"minimum": float64(1)},
+	}
+	data, err := json.Marshal(issue)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	want := `{"detail":"quantity must be 1 or greater","in":"body","minimum":1,"name":"lines[0].quantity","pointer":"/lines/0/quantity","type":"urn:problem-type:input-validation:schemaViolation","value":0}`
+	if string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+	var got Issue
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, issue) {
+		t.Errorf("Unmarshal() = %+v, want %+v", got, issue)
+	}
+}
+
+func TestProblem_Issues(t *testing.T) {
+	prob := InputValidation(
+		Issue{Type: TypeSchemaViolation, In: "query", Name: "limit", Value: "abc"},
+		Issue{Type: TypeUnknownParameter, In: "body", Name: "colour", Pointer: "/colour"},
+	)
+	data, err := json.Marshal(prob)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var decoded Problem
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	issues := decoded.Issues()
+	if len(issues) != 2 || issues[0].Name != "limit" || issues[0].Value != "abc" || issues[1].Pointer != "/colour" {
+		t.Errorf("Issues() = %+v", issues)
+	}
+
+	legacy := New(400, "")
+	legacy.Type = TypeBadRequest
+	issue := Problem{Type: TypeSchemaViolation}
+	_ = issue.Set("in", "path")
+	_ = issue.Set("name", "id")
+	_ = issue.Set("reason", "format")
+	_ = legacy.Set("issues", []Problem{issue})
+	if got := legacy.Issues(); len(got) != 1 || got[0].In != "path" || got[0].Extensions["reason"] != "format" {
+		t.Errorf("Issues() of problems = %+v", got)
+	}
+	if got := New(400, "").Issues(); got != nil {
+		t.Errorf("Issues() without issues = %+v", got)
+	}
+}
+
+func TestDedupeIssues(t *testing.T) {
+	a := Issue{Type: TypeSchemaViolation, In: "query", Name: "limit", Detail: "too small", Value: 0}
+	b := Issue{Type: TypeSchemaViolation, In: "query", Name: "limit", Detail: "too small", Value: -1}
+	got := DedupeIssues([]Issue{a, b, a})
+	if len(got) != 2 || got[0].Value != 0 || got[1].Value != -1 {
+		t.Errorf("DedupeIssues() = %+v", got)
+	}
+}
+
+func TestSortIssues(t *testing.T) {
+	issues := []Issue{
+		{In: "query", Name: "limit", Detail: "first"},
+		{In: "body", Pointer: "/b"},
+		{In: "body", Pointer: "/a"},
+		{In: "query", Name: "limit", Detail: "second"},
+		{In: "header", Name: "X-Tenant"},
+	}
+	SortIssues(issues)
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.In+issue.Pointer+issue.Name+issue.Detail)
+	}
+	want := []string{"body/a", "body/b", "headerX-Tenant", "querylimitfirst", "querylimitsecond"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SortIssues() = %v, want %v", got, want)
+	}
+}
+
+func TestProblem_AddIssues(t *testing.T) {
+	defer func(max int) { MaxIssues = max }(MaxIssues)
+	MaxIssues = 3
+	prob := New(400, "")
+	prob.Type = TypeBadRequest
+	for _, name := range []string{"a", "b"} {
+		if err := prob.AddIssues(Issue{Name: name}); err != nil {
+			t.Fatalf("AddIssues() error = %v", err)
+		}
+	}
+	if err := prob.AddIssues(Issue{Name: "c"}, Issue{Name: "d"}, Issue{Name: "e"}); err != nil {
+		t.Fatalf("AddIssues() error = %v", err)
+	}
+	if got := prob.Issues(); len(got) != 3 || got[2].Name != "c" {
+		t.Errorf("AddIssues() issues = %+v", got)
+	}
+	if got := prob.Get("omittedIssues"); got != 2 {
+		t.Errorf("AddIssues() omittedIssues = %v, want 2", got)
+	}
+
+	data, err := json.Marshal(prob)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var decoded Problem
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if err := decoded.AddIssues(Issue{Name: "f"}); err != nil {
+		t.Fatalf("AddIssues() error = %v", err)
+	}
+	if got := decoded.Get("omittedIssues"); got != 3 {
+		t.Errorf("AddIssues() decoded omittedIssues = %v, want 3", got)
+	}
+	if _, ok := decoded.Attributes["omittedissues"]; ok {
+		t.Errorf("AddIssues() kept the decoded omittedissues member")
+	}
+}
+
+func TestIssue_LocalizeRegisteredTitle(t *testing.T) {
+	reg := DefaultRegistry
+	defer func() { DefaultRegistry = reg }()
+	DefaultRegistry = NewRegistry()
+	_ = DefaultRegistry.Register(TypeInfo{Type: "urn:problem-type:test", Title: "Test", Titles: map[string]string{"de": "Testfehler"}})
+	issue := Issue{Type: "urn:problem-type:test", Title: "Test"}
+	if got := issue.Localize("de", NewTranslations()); got.Title != "Testfehler" {
+		t.Errorf("Localize() title = %v, want Testfehler", got.Title)
+	}
+}
@@ -0,0 +1,166 @@
+package problems
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// TypeFile is the JSON representation of a set of problem types that
+// can be loaded into a registry without recompiling.
+//
+//	{
+//	  "version": "2024-06-01",
+//	  "types": [
+//	    {
+//	      "type": "urn:problem-type:noAccessToken",
+//	      "status": 401,
+//	      "title": "No Access Token",
+//	      "titles": {"de": "Kein Zugriffstoken"},
+//	      "href": "https://api.example.com/problems/noAccessToken",
+//	      "extensions": [{"name": "realm", "type": "string"}]
+//	    }
+//	  ]
+//	}
+type TypeFile struct {
+	Version string     `json:"version,omitempty"`
+	Types   []TypeInfo `json:"types"`
+}
+
+// ReadTypeFile decodes and validates a type file
+func ReadTypeFile(r io.Reader) (*TypeFile, error) {
+	var file TypeFile
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return nil, Errorf(500, "Unable to read problem type file: %v", err)
+	}
+	if err := file.Validate(); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// Validate checks that every type URI is unique, every status is a
+// 4xx or 5xx code and every title is set.
+func (file *TypeFile) Validate() error {
+	var issues []string
+	seen := make(map[string]bool)
+	for i, info := range file.Types {
+		name := info.Type
+		if strings.TrimSpace(name) == "" || name == "about:blank" {
+			issues = append(issues, fmt.Sprintf("types[%d] has no type URI", i))
+			name = fmt.Sprintf("types[%d]", i)
+		} else if seen[name] {
+			issues = append(issues, fmt.Sprintf("%s is defined more than once", name))
+		}
+		seen[name] = true
+		if info.Status < 400 || info.Status > 599 {
+			issues = append(issues, fmt.Sprintf("%s has status %d; it must be between 400 and 599", name, info.Status))
+		}
+		if strings.TrimSpace(info.Title) == "" {
+			issues = append(issues, fmt.Sprintf("%s has no title", name))
+		}
+		for lang, title := range info.Titles {
+			if strings.TrimSpace(title) == "" {
+				issues = append(issues, fmt.Sprintf("%s has no %s title", name, lang))
+			}
+		}
+	}
+	if len(issues) > 0 {
+		return New(500, "Invalid problem type file: "+strings.Join(issues, "; "))
+	}
+	return nil
+}
+
+// Load reads a type file and registers each of its types
+func (reg *Registry) Load(r io.Reader) error {
+	return reg.load("", r)
+}
+
+// LoadFile registers the types defined in the file at path.  Loading the
+// same path again removes any types that are no longer in the file.
+func (reg *Registry) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return Errorf(500, "Unable to open problem type file: %v", err)
+	}
+	defer f.Close()
+	return reg.load(path, f)
+}
+
+func (reg *Registry) load(path string, r io.Reader) error {
+	file, err := ReadTypeFile(r)
+	if err != nil {
+		return err
+	}
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	loaded := make([]string, 0, len(file.Types))
+	for _, info := range file.Types {
+		reg.types[info.Type] = info
+		loaded = append(loaded, info.Type)
+	}
+	if path != "" {
+		current := make(map[string]bool)
+		for _, name := range loaded {
+			current[name] = true
+		}
+		for _, name := range reg.files[path] {
+			if !current[name] {
+				delete(reg.types, name)
+			}
+		}
+		reg.files[path] = loaded
+	}
+	if file.Version != "" {
+		reg.version = file.Version
+	}
+	reg.updateLocales()
+	return nil
+}
+
+// WatchFile loads the file at path and then polls it every interval,
+// reloading it when its modification time or size changes.  Reload
+// failures are passed to onError, if provided, and the previously loaded
+// types remain registered.  Watching stops when ctx is done.
+func (reg *Registry) WatchFile(ctx context.Context, path string, interval time.Duration, onError func(error)) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Errorf(500, "Unable to watch problem type file: %v", err)
+	}
+	if err := reg.LoadFile(path); err != nil {
+		return err
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		modified, size := info.ModTime(), info.Size()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				if onError != nil {
+					onError(Errorf(500, "Unable to watch problem type file: %v", err))
+				}
+				continue
+			}
+			if info.ModTime().Equal(modified) && info.Size() == size {
+				continue
+			}
+			modified, size = info.ModTime(), info.Size()
+			if err := reg.LoadFile(path); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,89 @@
+package problems
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadTypeFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{
+			name:    "Test valid file",
+			data:    `{"version": "1", "types": [{"type": "urn:problem-type:test", "status": 400, "title": "Test", "titles": {"de": "Prüfung"}}]}`,
+			wantErr: false,
+		},
+		{
+			name:    "Test duplicate type",
+			data:    `{"types": [{"type": "urn:problem-type:test", "status": 400, "title": "Test"}, {"type": "urn:problem-type:test", "status": 400, "title": "Test"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Test invalid status",
+			data:    `{"types": [{"type": "urn:problem-type:test", "status": 200, "title": "Test"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Test missing title",
+			data:    `{"types": [{"type": "urn:problem-type:test", "status": 400}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Test unknown field",
+			data:    `{"types": [{"type": "urn:problem-type:test", "status": 400, "title": "Test", "code": 1}]}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadTypeFile(strings.NewReader(tt.data)); (err != nil) != tt.wantErr {
+				t.Errorf("ReadTypeFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegistry_LoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "types.json")
+	write := func(data string) {
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	reg := NewRegistry()
+	write(`{"version": "1", "types": [{"type": "urn:problem-type:a", "status": 400, "title": "A"}, {"type": "urn:problem-type:b", "status": 409, "title": "B"}]}`)
+	if err := reg.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if len(reg.Types()) != 2 || reg.Version() != "1" {
+		t.Fatalf("LoadFile() loaded %v version %v", reg.Types(), reg.Version())
+	}
+
+	write(`{"version": "2", "types": [{"type": "urn:problem-type:a", "status": 400, "title": "A"}]}`)
+	if err := reg.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if _, ok := reg.Lookup("urn:problem-type:b"); ok {
+		t.Error("LoadFile() did not remove a type deleted from the file")
+	}
+	if reg.Version() != "2" {
+		t.Errorf("LoadFile() version = %v, want 2", reg.Version())
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing.json")
+	err := reg.LoadFile(missing)
+	if prob, ok := err.(*Problem); !ok || prob.Status != 500 || !strings.Contains(prob.Detail, missing) ||
+		!errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("LoadFile() error = %v, want a 500 with the path", err)
+	}
+	if err := NewTranslations().LoadCatalogFile(missing); err == nil || !strings.Contains(err.Error(), missing) {
+		t.Errorf("LoadCatalogFile() error = %v, want the path", err)
+	}
+}
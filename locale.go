@@ -0,0 +1,359 @@
+package problems
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is the language of the text built into the package and
+// the fallback when no requested language is available.
+const DefaultLocale = "en"
+
+// Translations holds localized problem titles, keyed by problem type, and
+// detail messages, keyed by the detail key set with Problem.SetDetailKey
+// or Problem.SetDetailMessage.  It is safe for concurrent use.
+type Translations struct {
+	mu      sync.RWMutex
+	titles  map[string]map[string]string
+	details map[string]map[string]Message
+}
+
+// DefaultTranslations are the translations used by Render
+var DefaultTranslations = NewTranslations()
+
+// NewTranslations creates an empty set of translations
+func NewTranslations() *Translations {
+	return &Translations{
+		titles:  make(map[string]map[string]string),
+		details: make(map[string]map[string]Message),
+	}
+}
+
+// AddTitle registers the title of a problem type in a language
+func (tr *Translations) AddTitle(locale, typeURI, title string) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	locale = normalizeLocale(locale)
+	if tr.titles[locale] == nil {
+		tr.titles[locale] = make(map[string]string)
+	}
+	tr.titles[locale][typeURI] = title
+}
+
+// AddDetail registers the detail text for a detail key in a language.  The
+// text may reference named parameters as {name}.
+func (tr *Translations) AddDetail(locale, key, detail string) {
+	tr.AddMessage(locale, key, Message{Text: detail})
+}
+
+// AddMessage registers the detail message for a detail key in a language
+func (tr *Translations) AddMessage(locale, key string, msg Message) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	locale = normalizeLocale(locale)
+	if tr.details[locale] == nil {
+		tr.details[locale] = make(map[string]Message)
+	}
+	tr.details[locale][key] = msg
+}
+
+// Title returns the title of the problem type in the language
+func (tr *Translations) Title(locale, typeURI string) (string, bool) {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+	title, ok := tr.titles[normalizeLocale(locale)][typeURI]
+	return title, ok
+}
+
+// Detail returns the detail text for the key in the language with the
+// parameters substituted
+func (tr *Translations) Detail(locale, key string, params map[string]interface{}) (string, bool) {
+	tr.mu.RLock()
+	msg, ok := tr.details[normalizeLocale(locale)][key]
+	tr.mu.RUnlock()
+	if !ok {
+		return "", false
+	}
+	return msg.Format(locale, params), true
+}
+
+// Locales lists the languages that have translations, sorted
+func (tr *Translations) Locales() []string {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+	seen := map[string]bool{DefaultLocale: true}
+	for locale := range tr.titles {
+		seen[locale] = true
+	}
+	for locale := range tr.details {
+		seen[locale] = true
+	}
+	locales := make([]string, 0, len(seen))
+	for locale := range seen {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// RegisterTitle adds a title translation to DefaultTranslations
+func RegisterTitle(locale, typeURI, title string) {
+	DefaultTranslations.AddTitle(locale, typeURI, title)
+}
+
+// RegisterDetail adds a detail translation to DefaultTranslations
+func RegisterDetail(locale, key, detail string) {
+	DefaultTranslations.AddDetail(locale, key, detail)
+}
+
+// normalizeLocale lower cases a language tag and uses - as the separator
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// NegotiateLocale picks the best of the available languages for an
+// Accept-Language header.  A requested language matches an available
+// language with the same tag or the same primary language, so `de-CH`
+// matches `de`.  The fallback is returned when nothing matches.
+func NegotiateLocale(acceptLanguage string, available []string, fallback string) string {
+	type preference struct {
+		tag     string
+		quality float64
+	}
+	var prefs []preference
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(part, ";")
+		tag := normalizeLocale(fields[0])
+		if tag == "" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		if quality > 0 {
+			prefs = append(prefs, preference{tag: tag, quality: quality})
+		}
+	}
+	sort.SliceStable(prefs, func(i, j int) bool {
+		return prefs[i].quality > prefs[j].quality
+	})
+
+	for _, pref := range prefs {
+		if pref.tag == "*" {
+			return fallback
+		}
+		for _, locale := range available {
+			if normalizeLocale(locale) == pref.tag {
+				return locale
+			}
+		}
+		primary := strings.Split(pref.tag, "-")[0]
+		for _, locale := range available {
+			if strings.Split(normalizeLocale(locale), "-")[0] == primary {
+				return locale
+			}
+		}
+	}
+	return fallback
+}
+
+// SetDetailKey identifies the detail text so it can be translated when the
+// problem is rendered.  The current Detail is used when there is no
+// translation for the negotiated language.
+func (prob *Problem) SetDetailKey(key string) {
+	prob.detailKey = key
+}
+
+// SetDetailMessage sets the detail from the message for key, formatted with
+// the named parameters.  The Detail is set from the DefaultLocale message
+// and translated when the problem is rendered.
+func (prob *Problem) SetDetailMessage(key string, params map[string]interface{}) {
+	prob.detailKey = key
+	prob.detailParams = params
+	if detail, ok := DefaultTranslations.Detail(DefaultLocale, key, params); ok {
+		prob.Detail = detail
+	}
+}
+
+// DetailParams returns the parameters of the detail message
+func (prob *Problem) DetailParams() map[string]interface{} {
+	return prob.detailParams
+}
+
+// ExposeDetailParams adds the detail key and parameters to the problem as
+// the messageKey and messageParams extension members so clients can build
+// their own text.  Like other extensions it requires a Type other than
+// about:blank.
+func (prob *Problem) ExposeDetailParams() error {
+	if err := prob.Set("messageKey", prob.detailKey); err != nil {
+		return err
+	}
+	return prob.Set("messageParams", prob.detailParams)
+}
+
+// DetailKey returns the key of the detail text
+func (prob *Problem) DetailKey() string {
+	return prob.detailKey
+}
+
+// Localize returns a copy of the problem with the title and detail in the
+// requested language.  Titles come from the translations or the Titles of
+// the registered problem type.
+func (prob *Problem) Localize(locale string, tr *Translations) *Problem {
+	localized, _ := prob.localize(locale, tr)
+	return localized
+}
+
+// localize is Localize, also reporting whether any text was translated
+func (prob *Problem) localize(locale string, tr *Translations) (*Problem, bool) {
+	if tr == nil {
+		tr = DefaultTranslations
+	}
+	localized := *prob
+	translated := false
+	if title, ok := tr.Title(locale, prob.Type); ok {
+		localized.Title, translated = title, true
+	} else if info, ok := LookupType(prob.Type); ok {
+		if title, ok := info.Titles[normalizeLocale(locale)]; ok {
+			localized.Title, translated = title, true
+		}
+	}
+	if prob.detailKey != "" {
+		if detail, ok := tr.Detail(locale, prob.detailKey, prob.detailParams); ok {
+			localized.Detail, translated = detail, true
+		}
+	}
+	if len(prob.Attributes) > 0 {
+		localized.Attributes = make(map[string]interface{}, len(prob.Attributes))
+		for k, v := range prob.Attributes {
+			value, ok := localizeValue(v, locale, tr)
+			localized.Attributes[k] = value
+			translated = translated || ok
+		}
+	}
+	return &localized, translated
+}
+
+// localizeValue localizes problems nested in an attribute, such as
+// issues, and reports whether any text was translated
+func localizeValue(value interface{}, locale string, tr *Translations) (interface{}, bool) {
+	translated := false
+	switch v := value.(type) {
+	case Problem:
+		localized, ok := v.localize(locale, tr)
+		return *localized, ok
+	case *Problem:
+		return v.localize(locale, tr)
+	case []Problem:
+		out := make([]Problem, len(v))
+		for i := range v {
+			localized, ok := v[i].localize(locale, tr)
+			out[i], translated = *localized, translated || ok
+		}
+		return out, translated
+	case Issue:
+		return v.localize(locale, tr)
+	case []Issue:
+		out := make([]Issue, len(v))
+		for i := range v {
+			var ok bool
+			out[i], ok = v[i].localize(locale, tr)
+			translated = translated || ok
+		}
+		return out, translated
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i := range v {
+			var ok bool
+			out[i], ok = localizeValue(v[i], locale, tr)
+			translated = translated || ok
+		}
+		return out, translated
+	default:
+		return value, false
+	}
+}
+
+// localeSources are the languages of the text in rendered problems that
+// doesn't come from the translations
+var localeSources = struct {
+	sync.RWMutex
+	sources []func() []string
+}{}
+
+// RegisterLocaleSource adds a source of text in rendered problems that
+// doesn't come from the translations, eg. the validator messages of input
+// validation issues.  Render only negotiates the languages every source
+// has, so a problem isn't rendered in a mix of languages.
+func RegisterLocaleSource(locales func() []string) {
+	localeSources.Lock()
+	defer localeSources.Unlock()
+	localeSources.sources = append(localeSources.sources, locales)
+}
+
+// AvailableLocales lists the languages Render negotiates: those of the
+// DefaultTranslations and of the titles of the DefaultRegistry types that
+// every source added with RegisterLocaleSource has too.  The DefaultLocale
+// is always available.
+func AvailableLocales() []string {
+	locales := DefaultTranslations.Locales()
+	seen := make(map[string]bool, len(locales))
+	for _, locale := range locales {
+		seen[locale] = true
+	}
+	for _, locale := range DefaultRegistry.Locales() {
+		if !seen[locale] {
+			seen[locale] = true
+			locales = append(locales, locale)
+		}
+	}
+	localeSources.RLock()
+	sources := localeSources.sources
+	localeSources.RUnlock()
+	for _, source := range sources {
+		locales = intersectLocales(locales, source())
+	}
+	return locales
+}
+
+// intersectLocales keeps the DefaultLocale and the locales that are in
+// other, by tag or by primary language
+func intersectLocales(locales, other []string) []string {
+	has := make(map[string]bool, len(other))
+	for _, locale := range other {
+		has[normalizeLocale(locale)] = true
+	}
+	kept := make([]string, 0, len(locales))
+	for _, locale := range locales {
+		tag := normalizeLocale(locale)
+		if tag == DefaultLocale || has[tag] || has[strings.Split(tag, "-")[0]] {
+			kept = append(kept, locale)
+		}
+	}
+	return kept
+}
+
+// negotiate localizes the problem for the request.  The Content-Language
+// of the response is only set when some text was translated; otherwise
+// the language of the text isn't known.
+func (prob *Problem) negotiate(w http.ResponseWriter, r *http.Request) *Problem {
+	if r == nil {
+		return prob
+	}
+	locale := NegotiateLocale(r.Header.Get("Accept-Language"), AvailableLocales(), DefaultLocale)
+	w.Header().Add("Vary", "Accept-Language")
+	localized, translated := prob.localize(locale, DefaultTranslations)
+	if translated {
+		w.Header().Set("Content-Language", locale)
+	}
+	return localized
+}
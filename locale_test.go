@@ -0,0 +1,109 @@
+package problems
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestNegotiateLocale(t *testing.T) {
+	available := []string{"en", "de", "fr-CA"}
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "Test empty header", header: "", want: "en"},
+		{name: "Test exact match", header: "de", want: "de"},
+		{name: "Test regional request", header: "de-CH, en;q=0.5", want: "de"},
+		{name: "Test regional translation", header: "fr", want: "fr-CA"},
+		{name: "Test quality order", header: "en;q=0.2, fr-CA;q=0.9", want: "fr-CA"},
+		{name: "Test unavailable language", header: "ja", want: "en"},
+		{name: "Test refused language", header: "de;q=0, ja", want: "en"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NegotiateLocale(tt.header, available, DefaultLocale); got != tt.want {
+				t.Errorf("NegotiateLocale() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProblem_RenderLocalized(t *testing.T) {
+	tr := DefaultTranslations
+	DefaultTranslations = NewTranslations()
+	defer func() { DefaultTranslations = tr }()
+	RegisterTitle("de", "urn:problem-type:test", "Testfehler")
+	RegisterDetail("de", "test.detail", "Etwas ist schiefgelaufen")
+
+	prob := New(409, "Something went wrong")
+	prob.Type = "urn:problem-type:test"
+	prob.Title = "Test Error"
+	prob.SetDetailKey("test.detail")
+
+	tests := []struct {
+		name         string
+		language     string
+		wantLanguage string
+		wantTitle    string
+		wantDetail   string
+	}{
+		{
+			name:         "Test German",
+			language:     "de-DE,de;q=0.9,en;q=0.8",
+			wantLanguage: "de",
+			wantTitle:    "Testfehler",
+			wantDetail:   "Etwas ist schiefgelaufen",
+		},
+		{
+			name:         "Test fallback",
+			language:     "es",
+			wantLanguage: "",
+			wantTitle:    "Test Error",
+			wantDetail:   "Something went wrong",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept-Language", tt.language)
+			w := httptest.NewRecorder()
+			_ = prob.Render(w, r)
+			if got := w.Header().Get("Content-Language"); got != tt.wantLanguage {
+				t.Errorf("Render() Content-Language = %v, want %v", got, tt.wantLanguage)
+			}
+			var body map[string]interface{}
+			_ = json.Unmarshal(w.Body.Bytes(), &body)
+			if body["title"] != tt.wantTitle || body["detail"] != tt.wantDetail {
+				t.Errorf("Render() = %v, want %v: %v", body, tt.wantTitle, tt.wantDetail)
+			}
+		})
+	}
+	if prob.Title != "Test Error" {
+		t.Errorf("Render() modified the problem title to %v", prob.Title)
+	}
+}
+
+func TestAvailableLocales(t *testing.T) {
+	reg, tr := DefaultRegistry, DefaultTranslations
+	defer func(sources []func() []string) {
+		DefaultRegistry, DefaultTranslations = reg, tr
+		localeSources.sources = sources
+	}(localeSources.sources)
+	DefaultRegistry, DefaultTranslations = NewRegistry(), NewTranslations()
+	RegisterDetail("de", "test.detail", "Etwas ist schiefgelaufen")
+	_ = DefaultRegistry.Register(TypeInfo{Type: "urn:problem-type:test", Titles: map[string]string{"fr-CA": "Erreur", "ja": "エラー"}})
+	if got, want := DefaultRegistry.Locales(), []string{"fr-ca", "ja"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Registry.Locales() = %v, want %v", got, want)
+	}
+	if got, want := AvailableLocales(), []string{"de", "en", "fr-ca", "ja"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AvailableLocales() = %v, want %v", got, want)
+	}
+	RegisterLocaleSource(func() []string { return []string{"en", "fr", "ja"} })
+	if got, want := AvailableLocales(), []string{"en", "fr-ca", "ja"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AvailableLocales() with a source = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,185 @@
+package problems
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// StatusClientClosedRequest is the non-standard status of a request
+// abandoned by the client before the response was written
+const StatusClientClosedRequest = 499
+
+// Detail keys of the problems created by the built-in error mappers.  The
+// English text is registered with the DefaultTranslations.
+const (
+	DetailNotFound  = "error.notFound"
+	DetailForbidden = "error.forbidden"
+	DetailTimeout   = "error.timeout"
+	DetailCanceled  = "error.canceled"
+)
+
+func init() {
+	for key, text := range map[string]string{
+		DetailNotFound:  "The requested resource cannot be found",
+		DetailForbidden: "Access to the resource is not permitted",
+		DetailTimeout:   "The request took too long to complete",
+		DetailCanceled:  "The request was canceled by the client",
+	} {
+		DefaultTranslations.AddDetail(DefaultLocale, key, text)
+	}
+	for _, mapper := range []ErrorMapper{
+		mapWrappedProblem, mapNotExist, mapPermission, mapContext, mapJSON, mapMaxBytes,
+	} {
+		RegisterErrorMapper(DefaultMapperPriority, mapper)
+	}
+}
+
+// ErrorMapper converts an error it recognizes to a problem and returns nil
+// for any other error
+type ErrorMapper func(err error) *Problem
+
+// DefaultMapperPriority is the priority of the built-in error mappers
+const DefaultMapperPriority = 0
+
+type errorMapper struct {
+	priority int
+	mapper   ErrorMapper
+}
+
+var errorMappers = struct {
+	sync.RWMutex
+	mappers []errorMapper
+}{}
+
+// RegisterErrorMapper adds a mapper used by FromError and MapError.
+// Mappers are tried from the highest priority to the lowest, and in the
+// order they were registered within a priority, until one returns a
+// problem.  Register with a priority above DefaultMapperPriority to
+// override the built-in mappers, or below it to only see the errors they
+// don't recognize.
+func RegisterErrorMapper(priority int, mapper ErrorMapper) {
+	errorMappers.Lock()
+	defer errorMappers.Unlock()
+	// MapError walks the slice without the lock, so it is replaced rather
+	// than changed in place
+	mappers := make([]errorMapper, len(errorMappers.mappers), len(errorMappers.mappers)+1)
+	copy(mappers, errorMappers.mappers)
+	mappers = append(mappers, errorMapper{priority, mapper})
+	sort.SliceStable(mappers, func(i, j int) bool {
+		return mappers[i].priority > mappers[j].priority
+	})
+	errorMappers.mappers = mappers
+}
+
+// MapError converts err to a problem with the registered error mappers.
+// A *Problem is returned as a copy.  nil is returned when no mapper
+// recognizes the error.
+//
+// The built-in mappers convert:
+//
+//   - a wrapped *Problem to a copy of the problem with err as its cause,
+//     so errors.Is and errors.As still see the wrapping but its text isn't
+//     serialized
+//   - fs.ErrNotExist and sql.ErrNoRows to 404 Not Found
+//   - fs.ErrPermission to 403 Forbidden
+//   - context.DeadlineExceeded to 504 Gateway Timeout
+//   - context.Canceled to 499 Client Closed Request
+//   - *json.SyntaxError and *json.UnmarshalTypeError to 400 Bad Request
+//   - *http.MaxBytesError to 413 Payload Too Large
+func MapError(err error) *Problem {
+	if prob, ok := err.(*Problem); ok {
+		return prob.clone()
+	}
+	errorMappers.RLock()
+	mappers := errorMappers.mappers
+	errorMappers.RUnlock()
+	for _, m := range mappers {
+		if prob := m.mapper(err); prob != nil {
+			return prob
+		}
+	}
+	return nil
+}
+
+// mappedProblem creates the problem of a recognized error, wrapping the
+// error so errors.Is and errors.As still find it
+func mappedProblem(status int, title string, err error, key string) *Problem {
+	prob := New(status, "")
+	prob.Title = title
+	prob.SetDetailMessage(key, nil)
+	prob.SetCause(err)
+	return prob
+}
+
+func mapWrappedProblem(err error) *Problem {
+	var prob *Problem
+	if errors.As(err, &prob) {
+		wrapped := prob.clone()
+		wrapped.SetCause(err)
+		return wrapped
+	}
+	return nil
+}
+
+func mapNotExist(err error) *Problem {
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, sql.ErrNoRows) {
+		return mappedProblem(http.StatusNotFound, http.StatusText(http.StatusNotFound), err, DetailNotFound)
+	}
+	return nil
+}
+
+func mapPermission(err error) *Problem {
+	if errors.Is(err, fs.ErrPermission) {
+		return mappedProblem(http.StatusForbidden, http.StatusText(http.StatusForbidden), err, DetailForbidden)
+	}
+	return nil
+}
+
+func mapContext(err error) *Problem {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return mappedProblem(http.StatusGatewayTimeout, http.StatusText(http.StatusGatewayTimeout), err, DetailTimeout)
+	case errors.Is(err, context.Canceled):
+		return mappedProblem(StatusClientClosedRequest, "Client Closed Request", err, DetailCanceled)
+	}
+	return nil
+}
+
+func mapJSON(err error) *Problem {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		prob := bodyProblem("", map[string]interface{}{"error": strings.TrimPrefix(syntaxErr.Error(), "json: ")})
+		prob.SetCause(err)
+		return prob
+	case errors.As(err, &typeErr):
+		issue := Issue{Type: TypeSchemaViolation, In: "body", Name: typeErr.Field}
+		issue.SetDetailMessage(DetailTypeMismatch, map[string]interface{}{
+			"name":  typeErr.Field,
+			"type":  jsonKind(typeErr.Type),
+			"value": typeErr.Value,
+		})
+		prob := InputValidation(issue)
+		prob.SetCause(err)
+		return prob
+	}
+	return nil
+}
+
+func mapMaxBytes(err error) *Problem {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		prob := tooLargeProblem(tooLarge.Limit)
+		prob.SetCause(err)
+		return prob
+	}
+	return nil
+}
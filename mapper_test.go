@@ -0,0 +1,135 @@
+package problems
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFromError_Mappers(t *testing.T) {
+	typeErr := json.Unmarshal([]byte(`{"count":"x"}`), &struct {
+		Count int `json:"count"`
+	}{})
+	syntaxErr := json.Unmarshal([]byte(`{x`), &struct{}{})
+	_, maxErr := io.ReadAll(http.MaxBytesReader(httptest.NewRecorder(), io.NopCloser(strings.NewReader("abc")), 1))
+	_, notExist := os.Open("/does/not/exist")
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"Test not exist", notExist, http.StatusNotFound},
+		{"Test no rows", fmt.Errorf("get user: %w", sql.ErrNoRows), http.StatusNotFound},
+		{"Test permission", os.ErrPermission, http.StatusForbidden},
+		{"Test deadline", context.DeadlineExceeded, http.StatusGatewayTimeout},
+		{"Test canceled", fmt.Errorf("query: %w", context.Canceled), StatusClientClosedRequest},
+		{"Test syntax", syntaxErr, http.StatusBadRequest},
+		{"Test type mismatch", typeErr, http.StatusBadRequest},
+		{"Test max bytes", maxErr, http.StatusRequestEntityTooLarge},
+		{"Test wrapped problem", fmt.Errorf("wrapped: %w", New(http.StatusConflict, "Conflict")), http.StatusConflict},
+		{"Test other", errors.New("boom"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prob := FromError(tt.err)
+			if prob.Status != tt.status {
+				t.Errorf("FromError() status = %d, want %d", prob.Status, tt.status)
+			}
+			if tt.status != http.StatusConflict && !errors.Is(prob, tt.err) {
+				t.Errorf("FromError() doesn't wrap %v", tt.err)
+			}
+		})
+	}
+}
+
+func TestFromError_Problem(t *testing.T) {
+	prob := New(http.StatusNotFound, "Not here")
+	if got := FromError(prob); got == prob || !reflect.DeepEqual(got, prob) {
+		t.Errorf("FromError() = %v, want a copy of the problem", got)
+	}
+}
+
+func TestRegisterErrorMapper(t *testing.T) {
+	defer func(mappers []errorMapper) { errorMappers.mappers = mappers }(errorMappers.mappers)
+	errTeapot := errors.New("teapot")
+	RegisterErrorMapper(DefaultMapperPriority-1, func(err error) *Problem {
+		return New(http.StatusServiceUnavailable, err.Error())
+	})
+	RegisterErrorMapper(DefaultMapperPriority+1, func(err error) *Problem {
+		if errors.Is(err, errTeapot) || errors.Is(err, sql.ErrNoRows) {
+			return New(http.StatusTeapot, err.Error())
+		}
+		return nil
+	})
+	tests := []struct {
+		err    error
+		status int
+	}{
+		{errTeapot, http.StatusTeapot},
+		{sql.ErrNoRows, http.StatusTeapot},
+		{os.ErrPermission, http.StatusForbidden},
+		{errors.New("other"), http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		if got := MapError(tt.err); got == nil || got.Status != tt.status {
+			t.Errorf("MapError(%v) = %v, want %d", tt.err, got, tt.status)
+		}
+	}
+}
+
+func TestRegisterErrorMapper_Concurrent(t *testing.T) {
+	defer func(mappers []errorMapper) { errorMappers.mappers = mappers }(errorMappers.mappers)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			RegisterErrorMapper(i%3-1, func(err error) *Problem { return nil })
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		if prob := MapError(os.ErrPermission); prob == nil || prob.Status != http.StatusForbidden {
+			t.Fatalf("MapError() = %v, want 403", prob)
+		}
+	}
+	<-done
+}
+
+func TestFromError_Copy(t *testing.T) {
+	prob := New(http.StatusConflict, "Already exists")
+	_ = prob.Set("Type", TypeBadRequest)
+	_ = prob.Set("sku", "A-1")
+	prob.Header().Set("Retry-After", "5")
+
+	got := FromError(prob)
+	got.Status = http.StatusInternalServerError
+	_ = got.Set("sku", "B-2")
+	got.Header().Set("Retry-After", "10")
+	if prob.Status != http.StatusConflict || prob.Get("sku") != "A-1" || prob.Header().Get("Retry-After") != "5" {
+		t.Errorf("FromError() changed the original problem: %+v", prob)
+	}
+
+	wrapping := fmt.Errorf("create order: %w", prob)
+	got = FromError(wrapping)
+	if got == prob || got.Status != http.StatusConflict || got.Type != TypeBadRequest {
+		t.Errorf("FromError() = %+v, want a copy of the problem", got)
+	}
+	if !errors.Is(got, wrapping) || !errors.Is(got, prob) {
+		t.Errorf("FromError() doesn't wrap %v", wrapping)
+	}
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if strings.Contains(string(data), "create order") {
+		t.Errorf("Marshal() = %s, want no text of the wrapping error", data)
+	}
+}
@@ -0,0 +1,227 @@
+package problems
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/de"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/es"
+	"github.com/go-playground/locales/fr"
+	"github.com/go-playground/locales/it"
+	"github.com/go-playground/locales/nl"
+	"github.com/go-playground/locales/pt"
+)
+
+// PluralParam is the message parameter that selects the plural form
+const PluralParam = "count"
+
+// Message is a detail message template.  Parameters are referenced by
+// name as {name}.  A message with plural forms selects the form for the
+// PluralParam parameter using the CLDR plural category of the language
+// (zero, one, two, few, many or other).
+//
+// In a catalog file a message is either a string or an object of plural
+// forms:
+//
+//	"resource.missing": "No resource {type}:{value} found"
+//	"issues.count": {"one": "{count} issue was found", "other": "{count} issues were found"}
+type Message struct {
+	Text   string
+	Plural map[string]string
+}
+
+// MarshalJSON writes the message as a string or an object of plural forms
+func (msg Message) MarshalJSON() ([]byte, error) {
+	if len(msg.Plural) > 0 {
+		return json.Marshal(msg.Plural)
+	}
+	return json.Marshal(msg.Text)
+}
+
+// UnmarshalJSON reads a message from a string or an object of plural forms
+func (msg *Message) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &msg.Text); err == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, &msg.Plural); err != nil {
+		return Errorf(500, "A message must be a string or an object of plural forms: %v", err)
+	}
+	if _, ok := msg.Plural["other"]; !ok {
+		return New(500, "A plural message requires an other form")
+	}
+	return nil
+}
+
+// Format substitutes the parameters into the message, choosing the plural
+// form for the language.  Unknown parameters are left in place.
+func (msg Message) Format(locale string, params map[string]interface{}) string {
+	text := msg.Text
+	if len(msg.Plural) > 0 {
+		text = msg.Plural["other"]
+		if count, ok := params[PluralParam]; ok {
+			if form, ok := msg.Plural[pluralCategory(locale, count)]; ok {
+				text = form
+			}
+		}
+	}
+	if len(params) == 0 {
+		return text
+	}
+	var out strings.Builder
+	for {
+		start := strings.IndexByte(text, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(text[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+		out.WriteString(text[:start])
+		if value, ok := params[text[start+1:end]]; ok {
+			out.WriteString(fmt.Sprint(value))
+		} else {
+			out.WriteString(text[start : end+1])
+		}
+		text = text[end+1:]
+	}
+	out.WriteString(text)
+	return out.String()
+}
+
+var pluralRules = struct {
+	sync.RWMutex
+	translators map[string]locales.Translator
+}{translators: make(map[string]locales.Translator)}
+
+// RegisterPluralRules makes the plural rules of a go-playground locale
+// available to messages in that language.  English, German, French,
+// Spanish, Italian, Dutch and Portuguese are registered by default.
+func RegisterPluralRules(translator locales.Translator) {
+	pluralRules.Lock()
+	defer pluralRules.Unlock()
+	pluralRules.translators[normalizeLocale(translator.Locale())] = translator
+}
+
+func init() {
+	for _, translator := range []locales.Translator{en.New(), de.New(), fr.New(), es.New(), it.New(), nl.New(), pt.New()} {
+		RegisterPluralRules(translator)
+	}
+}
+
+// pluralCategory returns the CLDR plural category of count in the language
+func pluralCategory(locale string, count interface{}) string {
+	num, digits, ok := pluralOperands(count)
+	if !ok {
+		return "other"
+	}
+	locale = normalizeLocale(locale)
+	pluralRules.RLock()
+	translator, ok := pluralRules.translators[locale]
+	if !ok {
+		translator, ok = pluralRules.translators[strings.Split(locale, "-")[0]]
+	}
+	pluralRules.RUnlock()
+	if !ok {
+		translator = en.New()
+	}
+	return strings.ToLower(translator.CardinalPluralRule(num, digits).String())
+}
+
+// pluralOperands returns the number and its count of visible fraction digits
+func pluralOperands(count interface{}) (float64, uint64, bool) {
+	value := reflect.ValueOf(count)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), 0, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), 0, true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), fractionDigits(strconv.FormatFloat(value.Float(), 'f', -1, 64)), true
+	case reflect.String:
+		f, err := strconv.ParseFloat(value.String(), 64)
+		return f, fractionDigits(value.String()), err == nil
+	default:
+		return 0, 0, false
+	}
+}
+
+func fractionDigits(number string) uint64 {
+	if i := strings.IndexByte(number, '.'); i >= 0 {
+		return uint64(len(number) - i - 1)
+	}
+	return 0
+}
+
+// MessageCatalog is the JSON file format of the detail messages of one
+// language:
+//
+//	{
+//	  "locale": "de",
+//	  "messages": {
+//	    "resource.missing": "Keine Ressource {type}:{value} gefunden"
+//	  }
+//	}
+type MessageCatalog struct {
+	Locale   string             `json:"locale"`
+	Messages map[string]Message `json:"messages"`
+}
+
+// LoadCatalog reads a message catalog and adds its messages
+func (tr *Translations) LoadCatalog(r io.Reader) error {
+	var catalog MessageCatalog
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&catalog); err != nil {
+		return Errorf(500, "Unable to read message catalog: %v", err)
+	}
+	if strings.TrimSpace(catalog.Locale) == "" {
+		return New(500, "A message catalog requires a locale")
+	}
+	for key, msg := range catalog.Messages {
+		tr.AddMessage(catalog.Locale, key, msg)
+	}
+	return nil
+}
+
+// LoadCatalogFile adds the messages of the catalog file at path
+func (tr *Translations) LoadCatalogFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return Errorf(500, "Unable to open message catalog: %v", err)
+	}
+	defer f.Close()
+	return tr.LoadCatalog(f)
+}
+
+// LoadCatalogFS adds the messages of every catalog in fsys matching the
+// pattern, eg. "messages/*.json"
+func (tr *Translations) LoadCatalogFS(fsys fs.FS, pattern string) error {
+	matches, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return Errorf(500, "Unable to find message catalogs: %v", err)
+	}
+	for _, name := range matches {
+		f, err := fsys.Open(name)
+		if err != nil {
+			return Errorf(500, "Unable to open message catalog: %v", err)
+		}
+		err = tr.LoadCatalog(f)
+		f.Close()
+		if err != nil {
+			return Errorf(500, "%s: %v", path.Base(name), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package problems
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMessage_Format(t *testing.T) {
+	tr := NewTranslations()
+	err := tr.LoadCatalog(strings.NewReader(`{
+		"locale": "fr",
+		"messages": {
+			"resource.missing": "Aucune ressource {type}:{value} trouvée",
+			"issues.count": {"one": "{count} problème", "other": "{count} problèmes"}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("LoadCatalog() error = %v", err)
+	}
+	tests := []struct {
+		name   string
+		locale string
+		key    string
+		params map[string]interface{}
+		want   string
+	}{
+		{
+			name:   "Test parameters",
+			locale: "fr",
+			key:    "resource.missing",
+			params: map[string]interface{}{"type": "User", "value": 123},
+			want:   "Aucune ressource User:123 trouvée",
+		},
+		{
+			name:   "Test missing parameter",
+			locale: "fr",
+			key:    "resource.missing",
+			params: map[string]interface{}{"type": "User"},
+			want:   "Aucune ressource User:{value} trouvée",
+		},
+		{
+			name:   "Test French singular zero",
+			locale: "fr",
+			key:    "issues.count",
+			params: map[string]interface{}{"count": 0},
+			want:   "0 problème",
+		},
+		{
+			name:   "Test plural",
+			locale: "fr",
+			key:    "issues.count",
+			params: map[string]interface{}{"count": 3},
+			want:   "3 problèmes",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tr.Detail(tt.locale, tt.key, tt.params)
+			if !ok || got != tt.want {
+				t.Errorf("Detail() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPluralCategory(t *testing.T) {
+	tests := []struct {
+		locale string
+		count  interface{}
+		want   string
+	}{
+		{locale: "en", count: 1, want: "one"},
+		{locale: "en", count: 0, want: "other"},
+		{locale: "en", count: 1.5, want: "other"},
+		{locale: "de-CH", count: 1, want: "one"},
+		{locale: "fr", count: 1.5, want: "one"},
+		{locale: "en", count: "many", want: "other"},
+	}
+	for _, tt := range tests {
+		if got := pluralCategory(tt.locale, tt.count); got != tt.want {
+			t.Errorf("pluralCategory(%v, %v) = %v, want %v", tt.locale, tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestMessage_UnmarshalJSON(t *testing.T) {
+	tr := NewTranslations()
+	err := tr.LoadCatalog(strings.NewReader(`{"locale": "en", "messages": {"issues.count": {"one": "{count} issue"}}}`))
+	if err == nil {
+		t.Error("LoadCatalog() accepted a plural message without an other form")
+	}
+}
@@ -0,0 +1,321 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"tjdavis.dev/problems"
+)
+
+// ReadDocument decodes an OpenAPI document in JSON form
+func ReadDocument(r io.Reader) (*Document, error) {
+	var doc Document
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, problems.Errorf(500, "Unable to read OpenAPI document: %v", err)
+	}
+	return &doc, nil
+}
+
+// CodeGenerator produces Go type constants, typed problem structs and
+// constructors from the problem responses and schemas of an OpenAPI
+// document.  Each response whose example has a type member produces a
+// constant and a constructor in the style of the standard package, eg.
+// MissingScopeResponse produces TypeMissingScope and
+// GetMissingScopeResponse(requiredscopes []string).
+type CodeGenerator struct {
+	// Package is the name of the generated package
+	Package string
+	// Command is recorded in the generated header
+	Command string
+}
+
+type genField struct {
+	Name     string
+	GoType   string
+	JSONName string
+	Optional bool
+}
+
+type genStruct struct {
+	Name    string
+	Problem bool
+	Fields  []genField
+}
+
+type genConstructor struct {
+	Name       string
+	Const      string
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Extensions []genField
+}
+
+type genFile struct {
+	Package      string
+	Command      string
+	Structs      []genStruct
+	Constructors []genConstructor
+}
+
+// Generate writes the formatted Go source for the document
+func (gen *CodeGenerator) Generate(w io.Writer, doc *Document) error {
+	file := genFile{Package: gen.Package, Command: gen.Command}
+	if file.Package == "" {
+		file.Package = "problemtypes"
+	}
+	if file.Command == "" {
+		file.Command = "problemgen"
+	}
+
+	structs := make(map[string]genStruct)
+	for _, name := range sortedKeys(doc.Components.Schemas) {
+		if name == "Problem" {
+			continue
+		}
+		structs[name] = schemaStruct(name, doc.Components.Schemas[name])
+		file.Structs = append(file.Structs, structs[name])
+	}
+
+	for _, name := range sortedKeys(doc.Components.Responses) {
+		constructor, ok := responseConstructor(name, doc.Components.Responses[name], structs)
+		if ok {
+			file.Constructors = append(file.Constructors, constructor)
+		}
+	}
+
+	var src bytes.Buffer
+	if err := codeTemplate.Execute(&src, file); err != nil {
+		return problems.FromError(err)
+	}
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return problems.Errorf(500, "Generated code is invalid: %v", err)
+	}
+	_, err = w.Write(formatted)
+	return err
+}
+
+func responseConstructor(name string, response Schema, structs map[string]genStruct) (genConstructor, bool) {
+	content := asSchema(asSchema(response["content"])[problems.ProblemMediaType])
+	example := asSchema(content["example"])
+	typeURI, _ := example["type"].(string)
+	if typeURI == "" || typeURI == "about:blank" {
+		return genConstructor{}, false
+	}
+	base := strings.TrimSuffix(name, "Response")
+	title, _ := example["title"].(string)
+	detail, ok := example["detail"].(string)
+	if !ok {
+		detail = title
+	}
+	constructor := genConstructor{
+		Name:   "Get" + base + "Response",
+		Const:  "Type" + base,
+		Type:   typeURI,
+		Title:  title,
+		Detail: detail,
+	}
+	if status, ok := example["status"].(float64); ok {
+		constructor.Status = int(status)
+	}
+	ref, _ := asSchema(content["schema"])["$ref"].(string)
+	if typed, ok := structs[strings.TrimPrefix(ref, "#/components/schemas/")]; ok && typed.Problem {
+		for _, field := range typed.Fields {
+			if !baseFields[field.JSONName] {
+				constructor.Extensions = append(constructor.Extensions, field)
+			}
+		}
+	}
+	return constructor, true
+}
+
+func schemaStruct(name string, schema Schema) genStruct {
+	st := genStruct{Name: name}
+	properties := make(map[string]interface{})
+	required := make(map[string]bool)
+	collect := func(part Schema) {
+		for k, v := range asSchema(part["properties"]) {
+			properties[k] = v
+		}
+		if list, ok := part["required"].([]interface{}); ok {
+			for _, r := range list {
+				required[fmt.Sprint(r)] = true
+			}
+		}
+	}
+	collect(schema)
+	if parts, ok := schema["allOf"].([]interface{}); ok {
+		for _, part := range parts {
+			part := asSchema(part)
+			if part["$ref"] == "#/components/schemas/Problem" {
+				st.Problem = true
+				continue
+			}
+			collect(part)
+		}
+	}
+	if st.Problem {
+		st.Fields = append(st.Fields,
+			genField{Name: "Type", GoType: "string", JSONName: "type"},
+			genField{Name: "Title", GoType: "string", JSONName: "title", Optional: true},
+			genField{Name: "Status", GoType: "int", JSONName: "status", Optional: true},
+			genField{Name: "Detail", GoType: "string", JSONName: "detail", Optional: true},
+			genField{Name: "Instance", GoType: "string", JSONName: "instance", Optional: true},
+		)
+	}
+	for _, prop := range sortedKeys(properties) {
+		if st.Problem && baseFields[prop] {
+			continue
+		}
+		st.Fields = append(st.Fields, genField{
+			Name:     goName(prop),
+			GoType:   goType(asSchema(properties[prop])),
+			JSONName: prop,
+			Optional: !required[prop],
+		})
+	}
+	return st
+}
+
+func goType(schema Schema) string {
+	if ref, ok := schema["$ref"].(string); ok {
+		return strings.TrimPrefix(ref, "#/components/schemas/")
+	}
+	switch schema["type"] {
+	case "string":
+		return "string"
+	case "integer":
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + goType(asSchema(schema["items"]))
+	case "object":
+		if additional, ok := schema["additionalProperties"]; ok {
+			return "map[string]" + goType(asSchema(additional))
+		}
+		return "map[string]interface{}"
+	default:
+		return "interface{}"
+	}
+}
+
+// goName converts a member name to an exported Go identifier
+func goName(name string) string {
+	var out strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		out.WriteRune(r)
+	}
+	if out.Len() == 0 || unicode.IsDigit([]rune(out.String())[0]) {
+		return "X" + out.String()
+	}
+	return out.String()
+}
+
+// reservedParams are the identifiers a parameter of a constructor can't
+// be named: the Go keywords, the predeclared types of goType and the
+// identifiers the generated constructors use
+var reservedParams = map[string]bool{
+	"break": true, "case": true, "chan": true, "const": true, "continue": true, "default": true,
+	"defer": true, "else": true, "fallthrough": true, "for": true, "func": true, "go": true,
+	"goto": true, "if": true, "import": true, "interface": true, "map": true, "package": true,
+	"range": true, "return": true, "select": true, "struct": true, "switch": true, "type": true,
+	"var": true, "string": true, "int": true, "float64": true, "bool": true,
+	"prob": true, "problems": true,
+}
+
+// paramName converts a member name to an unexported Go identifier
+func paramName(name string) string {
+	exported := []rune(goName(name))
+	exported[0] = unicode.ToLower(exported[0])
+	param := string(exported)
+	if reservedParams[param] {
+		return param + "Value"
+	}
+	return param
+}
+
+func asSchema(v interface{}) Schema {
+	switch s := v.(type) {
+	case Schema:
+		return s
+	case map[string]interface{}:
+		return Schema(s)
+	default:
+		return Schema{}
+	}
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch m := m.(type) {
+	case map[string]Schema:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]interface{}:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var codeTemplate = template.Must(template.New("code").Funcs(template.FuncMap{
+	"quote": strconv.Quote,
+	"param": paramName,
+	"tag": func(f genField) string {
+		if f.Optional {
+			return "`json:\"" + f.JSONName + ",omitempty\"`"
+		}
+		return "`json:\"" + f.JSONName + "\"`"
+	},
+}).Parse(`// Code generated by {{.Command}}. DO NOT EDIT.
+
+package {{.Package}}
+{{if .Constructors}}
+import "tjdavis.dev/problems"
+{{end}}
+{{if .Constructors}}// Problem types
+const (
+{{range .Constructors}}	{{.Const}} = {{quote .Type}}
+{{end}})
+{{end}}
+{{range .Structs}}
+// {{.Name}} is the typed form of the {{.Name}} schema
+type {{.Name}} struct {
+{{range .Fields}}	{{.Name}} {{.GoType}} {{tag .}}
+{{end}}}
+{{end}}
+{{range .Constructors}}
+// {{.Name}} creates a {{.Const}} problem
+func {{.Name}}({{range $i, $e := .Extensions}}{{if $i}}, {{end}}{{param .JSONName}} {{.GoType}}{{end}}) *problems.Problem {
+	prob := problems.New({{.Status}}, {{quote .Detail}})
+	_ = prob.Set("Type", {{.Const}})
+	_ = prob.Set("Title", {{quote .Title}})
+{{range .Extensions}}	_ = prob.Set({{quote .JSONName}}, {{param .JSONName}})
+{{end}}	return prob
+}
+{{end}}`))
@@ -0,0 +1,128 @@
+package openapi
+
+import (
+	"bytes"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+	"testing"
+
+	"tjdavis.dev/problems"
+)
+
+func TestCodeGenerator_Generate(t *testing.T) {
+	type OutOfCreditProblem struct {
+		Type    string   `json:"type"`
+		Title   string   `json:"title,omitempty"`
+		Balance int      `json:"balance"`
+		Account []string `json:"accounts,omitempty"`
+	}
+	reg := problems.NewRegistry()
+	_ = reg.Register(problems.TypeInfo{
+		Type:   "https://example.com/problems/out-of-credit",
+		Title:  "You do not have enough credit.",
+		Status: 403,
+	})
+	var spec bytes.Buffer
+	gen := Generator{Registry: reg, Schemas: map[string]interface{}{"OutOfCreditProblem": OutOfCreditProblem{}}}
+	_ = gen.Write(&spec)
+
+	doc, err := ReadDocument(&spec)
+	if err != nil {
+		t.Fatalf("ReadDocument() error = %v", err)
+	}
+	var out bytes.Buffer
+	codegen := CodeGenerator{Package: "billing"}
+	if err := codegen.Generate(&out, doc); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	for _, want := range []string{
+		"package billing",
+		`TypeOutOfCredit = "https://example.com/problems/out-of-credit"`,
+		"Balance  int      `json:\"balance\"`",
+		"func GetOutOfCreditResponse(accounts []string, balance int) *problems.Problem {",
+		`_ = prob.Set("balance", balance)`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Generate() is missing %q in\n%s", want, out.String())
+		}
+	}
+}
+
+func TestCodeGenerator_Generate_ReservedNames(t *testing.T) {
+	type CollisionProblem struct {
+		Type     string `json:"type"`
+		Prob     string `json:"prob"`
+		Problems int    `json:"problems"`
+		Int      string `json:"int"`
+		Count    int    `json:"count"`
+	}
+	reg := problems.NewRegistry()
+	_ = reg.Register(problems.TypeInfo{Type: "https://example.com/problems/collision", Title: "Collision", Status: 409})
+	var spec bytes.Buffer
+	gen := Generator{Registry: reg, Schemas: map[string]interface{}{"CollisionProblem": CollisionProblem{}}}
+	_ = gen.Write(&spec)
+	doc, err := ReadDocument(&spec)
+	if err != nil {
+		t.Fatalf("ReadDocument() error = %v", err)
+	}
+	var out bytes.Buffer
+	if err := (&CodeGenerator{Package: "billing"}).Generate(&out, doc); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if want := "func GetCollisionResponse(count int, intValue string, probValue string, problemsValue int) *problems.Problem {"; !strings.Contains(out.String(), want) {
+		t.Errorf("Generate() is missing %q in\n%s", want, out.String())
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "problems_gen.go", out.Bytes(), 0)
+	if err != nil {
+		t.Fatalf("generated code doesn't parse: %v\n%s", err, out.String())
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := conf.Check("billing", fset, []*ast.File{file}, nil); err != nil {
+		t.Errorf("generated code doesn't compile: %v\n%s", err, out.String())
+	}
+}
+
+func TestCodeGenerator_Generate_NoConstructors(t *testing.T) {
+	type LimitProblem struct {
+		Type  string `json:"type"`
+		Limit int    `json:"limit"`
+	}
+	tests := []struct {
+		name string
+		doc  *Document
+	}{
+		{"Test empty spec", &Document{}},
+		{"Test spec without examples", &Document{Components: Components{
+			Schemas: map[string]Schema{"LimitProblem": structSchema(reflect.TypeOf(LimitProblem{}), nil)},
+			Responses: map[string]Schema{"LimitResponse": {
+				"description": "The limit was reached",
+				"content": map[string]interface{}{problems.ProblemMediaType: map[string]interface{}{
+					"schema": map[string]interface{}{"$ref": "#/components/schemas/LimitProblem"},
+				}},
+			}},
+		}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := (&CodeGenerator{Package: "billing"}).Generate(&out, tt.doc); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, "problems_gen.go", out.Bytes(), 0)
+			if err != nil {
+				t.Fatalf("generated code doesn't parse: %v\n%s", err, out.String())
+			}
+			conf := types.Config{Importer: importer.Default()}
+			if _, err := conf.Check("billing", fset, []*ast.File{file}, nil); err != nil {
+				t.Errorf("generated code doesn't compile: %v\n%s", err, out.String())
+			}
+		})
+	}
+}
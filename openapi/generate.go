@@ -0,0 +1,3 @@
+package openapi
+
+//go:generate go run ../cmd/problems-openapi -o problems-v1.json
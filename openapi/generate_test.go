@@ -0,0 +1,27 @@
+package openapi_test
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"tjdavis.dev/problems/openapi"
+	"tjdavis.dev/problems/standard"
+)
+
+// TestProblemsV1 fails when problems-v1.json is out of date; run go generate
+// to update it
+func TestProblemsV1(t *testing.T) {
+	want, err := os.ReadFile("problems-v1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gen := openapi.Generator{Schemas: standard.Schemas, TypeSchemas: standard.SchemaTypes}
+	var got bytes.Buffer
+	if err := gen.Write(&got); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Bytes(), want) {
+		t.Error("problems-v1.json is out of date, run go generate ./openapi")
+	}
+}
@@ -0,0 +1,324 @@
+/*
+Package openapi generates OpenAPI 3 components from the registered problem
+types so the published specification is derived from the code.  The output
+is JSON with sorted keys, so regenerating an unchanged registry produces an
+identical document.
+*/
+package openapi
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"tjdavis.dev/problems"
+)
+
+// Version is the OpenAPI version of the generated document
+const Version = "3.0.3"
+
+// Schema is a JSON schema object
+type Schema map[string]interface{}
+
+// Document is the generated OpenAPI document
+type Document struct {
+	OpenAPI    string                 `json:"openapi"`
+	Info       map[string]string      `json:"info"`
+	Paths      map[string]interface{} `json:"paths"`
+	Components Components             `json:"components"`
+}
+
+// Components holds the generated schemas and responses
+type Components struct {
+	Schemas   map[string]Schema `json:"schemas"`
+	Responses map[string]Schema `json:"responses"`
+}
+
+// Generator builds OpenAPI components from a problem registry and typed
+// problem structs.
+type Generator struct {
+	// Title and Version populate the info object of the document
+	Title   string
+	Version string
+	// Registry provides the problem types.  nil uses the DefaultRegistry.
+	Registry *problems.Registry
+	// Schemas are typed problem structs keyed by schema name.  A struct
+	// named after a registered type (eg. MissingScopeProblem for
+	// urn:problem-type:missingScope) replaces the schema derived from the
+	// type's extensions.
+	Schemas map[string]interface{}
+	// TypeSchemas maps type URIs to the name of the schema in Schemas that
+	// describes them, for structs that aren't named after their type.
+	TypeSchemas map[string]string
+}
+
+// baseFields are the RFC7807 members described by the Problem schema
+var baseFields = map[string]bool{
+	"type":     true,
+	"title":    true,
+	"status":   true,
+	"detail":   true,
+	"instance": true,
+}
+
+// ProblemSchema is the schema of the RFC7807 members as rendered by
+// problems.Problem
+func ProblemSchema() Schema {
+	return Schema{
+		"description": "A Problem Details object (RFC 7807)",
+		"type":        "object",
+		"properties": map[string]Schema{
+			"type": {
+				"type":        "string",
+				"format":      "uri",
+				"description": "An absolute URI that identifies the problem type",
+				"default":     "about:blank",
+			},
+			"title": {
+				"type":        "string",
+				"description": "A short summary of the problem type",
+			},
+			"status": {
+				"type":        "integer",
+				"format":      "int32",
+				"description": "The HTTP status code generated by the origin server for this occurrence of the problem",
+				"minimum":     400,
+				"maximum":     599,
+			},
+			"detail": {
+				"type":        "string",
+				"description": "A human-readable explanation specific to this occurrence of the problem",
+			},
+			"instance": {
+				"type":        "string",
+				"format":      "uri",
+				"description": "A URI that identifies the specific occurrence of the problem",
+			},
+		},
+	}
+}
+
+// Name returns the component name for a problem type, which is the last
+// segment of the type URI in upper camel case.
+// urn:problem-type:input-validation:schemaViolation becomes SchemaViolation.
+func Name(typeURI string) string {
+	segment := typeURI
+	if i := strings.LastIndexAny(segment, ":/"); i >= 0 {
+		segment = segment[i+1:]
+	}
+	var name strings.Builder
+	upper := true
+	for _, r := range segment {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		name.WriteRune(r)
+	}
+	return name.String()
+}
+
+// Generate builds the OpenAPI document
+func (gen *Generator) Generate() *Document {
+	reg := gen.Registry
+	if reg == nil {
+		reg = problems.DefaultRegistry
+	}
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    map[string]string{"title": gen.Title, "version": gen.Version},
+		Paths:   map[string]interface{}{},
+		Components: Components{
+			Schemas:   map[string]Schema{"Problem": ProblemSchema()},
+			Responses: map[string]Schema{},
+		},
+	}
+	if doc.Info["title"] == "" {
+		doc.Info["title"] = "RFC7807 Problem Types"
+	}
+	if doc.Info["version"] == "" {
+		doc.Info["version"] = reg.Version()
+	}
+
+	for name, value := range gen.Schemas {
+		doc.Components.Schemas[name] = structSchema(reflect.TypeOf(value), gen.Schemas)
+	}
+
+	for _, info := range reg.Types() {
+		name := Name(info.Type)
+		schemaName := "Problem"
+		if typed, ok := gen.TypeSchemas[info.Type]; ok {
+			schemaName = typed
+		} else if _, ok := doc.Components.Schemas[name+"Problem"]; ok {
+			schemaName = name + "Problem"
+		} else if len(info.Extensions) > 0 {
+			schemaName = name + "Problem"
+			doc.Components.Schemas[schemaName] = extensionSchema(info)
+		}
+		example, _ := json.Marshal(info.Example())
+		var exampleValue interface{}
+		_ = json.Unmarshal(example, &exampleValue)
+		description := info.Description
+		if description == "" {
+			description = info.Title
+		}
+		doc.Components.Responses[name+"Response"] = Schema{
+			"description": description,
+			"content": map[string]Schema{
+				problems.ProblemMediaType: {
+					"schema":  Schema{"$ref": "#/components/schemas/" + schemaName},
+					"example": exampleValue,
+				},
+			},
+		}
+	}
+	return doc
+}
+
+// Write generates the document and writes it as indented JSON
+func (gen *Generator) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(gen.Generate())
+}
+
+func extensionSchema(info problems.TypeInfo) Schema {
+	properties := make(map[string]Schema)
+	for _, ext := range info.Extensions {
+		// Extension members are rendered in lower case
+		property := Schema(ext.Schema())
+		if ext.Type == "array" {
+			property["items"] = Schema{}
+		}
+		properties[strings.ToLower(ext.Name)] = property
+	}
+	return Schema{
+		"type": "object",
+		"allOf": []Schema{
+			{"$ref": "#/components/schemas/Problem"},
+			{"type": "object", "properties": properties},
+		},
+	}
+}
+
+// structSchema converts a typed problem struct.  The RFC7807 members are
+// referenced from the Problem schema and the remaining fields become the
+// extension properties.
+func structSchema(t reflect.Type, named map[string]interface{}) Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	properties, required := fieldSchemas(t, named)
+	isProblem := false
+	for name := range properties {
+		if baseFields[name] {
+			isProblem = true
+			delete(properties, name)
+		}
+	}
+	var extensionRequired []string
+	for _, name := range required {
+		if !baseFields[name] {
+			extensionRequired = append(extensionRequired, name)
+		}
+	}
+	object := Schema{"type": "object", "properties": properties}
+	if len(extensionRequired) > 0 {
+		object["required"] = extensionRequired
+	}
+	if !isProblem {
+		return object
+	}
+	return Schema{
+		"type": "object",
+		"allOf": []Schema{
+			{"$ref": "#/components/schemas/Problem"},
+			object,
+		},
+	}
+}
+
+func fieldSchemas(t reflect.Type, named map[string]interface{}) (map[string]Schema, []string) {
+	properties := make(map[string]Schema)
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			embedded, embeddedRequired := fieldSchemas(field.Type, named)
+			for k, v := range embedded {
+				properties[k] = v
+			}
+			required = append(required, embeddedRequired...)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = typeSchema(field.Type, named)
+		if !strings.Contains(tag, "omitempty") && field.Type.Kind() != reflect.Ptr {
+			required = append(required, name)
+		}
+	}
+	sort.Strings(required)
+	return properties, required
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func typeSchema(t reflect.Type, named map[string]interface{}) Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	for name, value := range named {
+		vt := reflect.TypeOf(value)
+		for vt.Kind() == reflect.Ptr {
+			vt = vt.Elem()
+		}
+		if vt == t {
+			return Schema{"$ref": "#/components/schemas/" + name}
+		}
+	}
+	switch {
+	case t == timeType:
+		return Schema{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.String:
+		return Schema{"type": "string"}
+	case t.Kind() == reflect.Bool:
+		return Schema{"type": "boolean"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		return Schema{"type": "integer"}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return Schema{"type": "number"}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		return Schema{"type": "array", "items": typeSchema(t.Elem(), named)}
+	case t.Kind() == reflect.Map:
+		return Schema{"type": "object", "additionalProperties": typeSchema(t.Elem(), named)}
+	case t.Kind() == reflect.Struct:
+		properties, required := fieldSchemas(t, named)
+		schema := Schema{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	default:
+		// interface{} values may hold any type
+		return Schema{}
+	}
+}
@@ -0,0 +1,65 @@
+package openapi
+
+import (
+	"bytes"
+	"testing"
+
+	"tjdavis.dev/problems"
+)
+
+func TestName(t *testing.T) {
+	tests := []struct {
+		typeURI string
+		want    string
+	}{
+		{typeURI: "urn:problem-type:noAccessToken", want: "NoAccessToken"},
+		{typeURI: "urn:problem-type:input-validation:schemaViolation", want: "SchemaViolation"},
+		{typeURI: "https://example.com/problems/out-of-credit", want: "OutOfCredit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typeURI, func(t *testing.T) {
+			if got := Name(tt.typeURI); got != tt.want {
+				t.Errorf("Name() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	type CreditProblem struct {
+		Type    string   `json:"type"`
+		Balance int      `json:"balance"`
+		Account []string `json:"accounts,omitempty"`
+	}
+	reg := problems.NewRegistry()
+	_ = reg.Register(problems.TypeInfo{
+		Type:       "urn:problem-type:credit",
+		Title:      "Out of credit",
+		Status:     403,
+		Extensions: []problems.Extension{{Name: "Balance", Type: "integer", Example: 30}},
+	})
+	_ = reg.Register(problems.TypeInfo{Type: "urn:problem-type:plain", Title: "Plain", Status: 400})
+	gen := Generator{Registry: reg, Version: "1"}
+
+	doc := gen.Generate()
+	if _, ok := doc.Components.Schemas["CreditProblem"]; !ok {
+		t.Errorf("Generate() schemas = %v, want CreditProblem", doc.Components.Schemas)
+	}
+	ref := doc.Components.Responses["PlainResponse"]["content"].(map[string]Schema)[problems.ProblemMediaType]["schema"]
+	if ref.(Schema)["$ref"] != "#/components/schemas/Problem" {
+		t.Errorf("Generate() PlainResponse schema = %v", ref)
+	}
+
+	gen.Schemas = map[string]interface{}{"CreditProblem": CreditProblem{}}
+	typed := gen.Generate().Components.Schemas["CreditProblem"]["allOf"].([]Schema)[1]
+	if typed["required"].([]string)[0] != "balance" {
+		t.Errorf("Generate() typed schema = %v", typed)
+	}
+
+	var first, second bytes.Buffer
+	_ = gen.Write(&first)
+	_ = gen.Write(&second)
+	if !bytes.Equal(first.Bytes(), second.Bytes()) {
+		t.Error("Write() output is not deterministic")
+	}
+}
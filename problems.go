@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"io"
 	"net/http"
+	"os"
 	"reflect"
 	"strconv"
 	"strings"
@@ -21,15 +23,6 @@ type renderType string
 var jsonType renderType = "json"
 var xmlType renderType = "xml"
 
-type Error interface {
-	error
-	Set(key string, value interface{}) Error
-	StatusCode() int
-	Get(string) interface{}
-	// ExtraFields lists the addon fields that can be retrieved with `Get`
-	ExtraFields() []string
-}
-
 // Problem is an RFC7807 representation of an error
 type Problem struct {
 	// Type is a URI reference [RFC3986] that identifies the
@@ -59,6 +52,13 @@ type Problem struct {
 	// and cannot be `about:blank`
 	Attributes map[string]interface{} `json:"vars,omitempty" xml:"vars,omitempty"`
 	err        error
+	// cause is wrapped like err but isn't serialized
+	cause     error
+	detailKey string
+	// detailParams are the named parameters of the detail message
+	detailParams map[string]interface{}
+	// header holds the response headers applied by Render
+	header http.Header
 }
 
 // Error returns a string representation of the problem to meet the Error interface definition
@@ -68,9 +68,19 @@ func (prob *Problem) Error() string {
 
 // Unwrap returns the underlying error or nil
 func (prob *Problem) Unwrap() error {
+	if prob.cause != nil {
+		return prob.cause
+	}
 	return prob.err
 }
 
+// SetCause sets the error the problem wraps, so errors.Is and errors.As
+// find it.  Unlike the error of FromError or Errorf, the cause isn't
+// serialized, so its text can't leak internal details to clients.
+func (prob *Problem) SetCause(err error) {
+	prob.cause = err
+}
+
 // Set sets the extended attribute identified by key to value
 // Setting anything other than the basic attributes requires a type other than `about:blank`
 func (prob *Problem) Set(key string, value interface{}) error {
@@ -91,8 +101,7 @@ func (prob *Problem) Set(key string, value interface{}) error {
 		prob.Instance = fmt.Sprint(value)
 	default:
 		if prob.Type == "" || prob.Type == "about:blank" {
-			err := FromError(prob)
-			err.Detail = fmt.Sprintf("Cannot set extended attribute (%s) unless Type is set", key)
+			err := Errorf(500, "Cannot set extended attribute (%s) unless Type is set", key)
 			err.PrettyPrint()
 			return err
 		}
@@ -130,12 +139,28 @@ func (prob *Problem) Get(key string) interface{} {
 }
 
 // Render will output the error as an HTTP response
+// The title and detail are translated to the best language for the
+// request's Accept-Language header.
 func (prob *Problem) Render(w http.ResponseWriter, r *http.Request) error {
+	localized := prob.negotiate(w, r)
+	for key, values := range prob.header {
+		w.Header()[key] = append([]string(nil), values...)
+	}
 	w.Header().Set("Content-Type", ProblemMediaType)
 	if prob.Status != 0 {
 		w.WriteHeader(prob.Status)
 	}
-	return json.NewEncoder(w).Encode(prob)
+	return json.NewEncoder(w).Encode(localized)
+}
+
+// Header returns the response headers that Render sets with the problem,
+// such as the Allow header of a 405 or the Retry-After of a 503.  The
+// headers aren't part of the problem document.
+func (prob *Problem) Header() http.Header {
+	if prob.header == nil {
+		prob.header = make(http.Header)
+	}
+	return prob.header
 }
 
 func (prob *Problem) MarshalJSON() ([]byte, error) {
@@ -157,11 +182,13 @@ func (prob *Problem) Marshal(renderAs renderType) ([]byte, error) {
 	for i := 0; i < subjectType.NumField(); i++ {
 		field := subjectType.Field(i)
 		name := subjectType.Field(i).Name
-		if name != "Attributes" && name != "err" {
+		if name != "Attributes" && field.PkgPath == "" {
 			var key string
 			var ok bool
 			if key, ok = field.Tag.Lookup(string(renderAs)); !ok {
-				key = name
+				if key, ok = field.Tag.Lookup(string(jsonType)); !ok {
+					key = name
+				}
 			}
 			if strings.HasSuffix(key, "omitempty") {
 				if len(fmt.Sprintf("%v", subjectValue.FieldByName(name).Interface())) == 0 {
@@ -189,7 +216,7 @@ func (prob *Problem) Marshal(renderAs renderType) ([]byte, error) {
 	case jsonType:
 		return json.Marshal(out)
 	case xmlType:
-		return xml.Marshal(out)
+		return marshalXML(out)
 	default:
 		return nil, New(500, "Invalid Marshal type specified")
 	}
@@ -264,6 +291,25 @@ func (p *Problem) GetTitle() string {
 	return p.Title
 }
 
+// clone returns a copy of the problem that shares no maps with it
+func (prob *Problem) clone() *Problem {
+	c := *prob
+	if prob.Attributes != nil {
+		c.Attributes = make(map[string]interface{}, len(prob.Attributes))
+		for k, v := range prob.Attributes {
+			c.Attributes[k] = v
+		}
+	}
+	if prob.detailParams != nil {
+		c.detailParams = make(map[string]interface{}, len(prob.detailParams))
+		for k, v := range prob.detailParams {
+			c.detailParams[k] = v
+		}
+	}
+	c.header = prob.header.Clone()
+	return &c
+}
+
 // FromErrorWithStatus creates a new problem from the
 // provided error but sets the status to the one provided
 // rather than the default of 500.
@@ -275,8 +321,18 @@ func FromErrorWithStatus(status int, err error) *Problem {
 	return prob
 }
 
-// FromError creates a new problem from the provided error
+// FromError creates a new problem from the provided error.  The
+// registered error mappers convert the errors they recognize, such as
+// fs.ErrNotExist to a 404; see MapError.  Any other error is a 500.
+//
+// A *Problem, or an error wrapping one, keeps its status, type and
+// members: a copy is returned, so changing it leaves the original alone.
+// Before the error mappers, FromError returned the problem itself with
+// its status forced to 500.
 func FromError(err error) *Problem {
+	if prob := MapError(err); prob != nil {
+		return prob
+	}
 	return FromErrorWithStatus(http.StatusInternalServerError, err)
 }
 
@@ -290,14 +346,21 @@ func Wrap(err error) *Problem {
 	return prob
 }
 
+// PrettyPrint writes the problem as indented JSON to stdout
 func (prob *Problem) PrettyPrint() {
-	pp, err := json.MarshalIndent(prob, "", "  ")
-	if err != nil {
+	if err := prob.Fprint(os.Stdout); err != nil {
 		fmt.Println(err)
-		return
 	}
-	fmt.Println(string(pp))
+}
 
+// Fprint writes the problem as indented JSON to w
+func (prob *Problem) Fprint(w io.Writer) error {
+	pp, err := json.MarshalIndent(prob, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(pp))
+	return err
 }
 
 // StatusCode returns the status of the problem
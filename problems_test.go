@@ -3,7 +3,9 @@ package problems
 import (
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -209,6 +211,26 @@ func TestProblem_Render(t *testing.T) {
 	}
 }
 
+func TestProblem_Header(t *testing.T) {
+	prob := New(http.StatusServiceUnavailable, "Down for maintenance")
+	prob.Header().Set("Retry-After", "120")
+	prob.Header().Add("Link", "</status>; rel=\"status\"")
+	w := httptest.NewRecorder()
+	w.Header().Set("Retry-After", "5")
+	if err := prob.Render(w, httptest.NewRequest("GET", "/", nil)); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if got := w.Header().Get("Retry-After"); got != "120" {
+		t.Errorf("Render() Retry-After = %v, want 120", got)
+	}
+	if got := w.Header().Get("Link"); got == "" {
+		t.Error("Render() didn't set the Link header")
+	}
+	if w.Code != http.StatusServiceUnavailable || strings.Contains(w.Body.String(), "Retry-After") {
+		t.Errorf("Render() = %d %s", w.Code, w.Body)
+	}
+}
+
 func TestNew(t *testing.T) {
 	type args struct {
 		status  int
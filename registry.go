@@ -0,0 +1,151 @@
+package problems
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Extension describes an extension member that a problem type adds
+// to the standard RFC7807 fields.
+type Extension struct {
+	// Name is the member name as it appears in the rendered problem
+	Name string `json:"name"`
+	// Type is the JSON schema type of the member (string, integer, array, ...)
+	Type string `json:"type,omitempty"`
+	// Description is a human-readable explanation of the member
+	Description string `json:"description,omitempty"`
+	// Example is a sample value used when documenting the member
+	Example interface{} `json:"example,omitempty"`
+}
+
+// TypeInfo documents a registered problem type.
+type TypeInfo struct {
+	// Type is the URI that identifies the problem type
+	Type string `json:"type"`
+	// Title is the short, human-readable summary of the problem type
+	Title string `json:"title"`
+	// Titles are localized titles keyed by language tag
+	Titles map[string]string `json:"titles,omitempty"`
+	// Status is the default HTTP status code for the problem type
+	Status int `json:"status,omitempty"`
+	// Href is an absolute URL of human-readable documentation for the type
+	Href string `json:"href,omitempty"`
+	// Description is the long form documentation for the problem type
+	Description string `json:"description,omitempty"`
+	// Extensions lists the extension members the problem type may carry
+	Extensions []Extension `json:"extensions,omitempty"`
+}
+
+// Example builds a sample problem of the registered type, using the
+// example value of each extension member.
+func (info TypeInfo) Example() *Problem {
+	prob := New(info.Status, info.Description)
+	prob.Type = info.Type
+	prob.Title = info.Title
+	for _, ext := range info.Extensions {
+		if ext.Example != nil {
+			_ = prob.Set(ext.Name, ext.Example)
+		}
+	}
+	return prob
+}
+
+// Registry holds the set of known problem types.  It is safe for
+// concurrent use.
+type Registry struct {
+	mu      sync.RWMutex
+	types   map[string]TypeInfo
+	version string
+	// files tracks the types loaded from each file so a reload can
+	// remove the types that are no longer defined
+	files map[string][]string
+	// locales are the languages of the Titles of the types, updated as
+	// types are registered so Render doesn't scan the types
+	locales []string
+}
+
+// DefaultRegistry is the registry used by the package level functions
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{types: make(map[string]TypeInfo), files: make(map[string][]string)}
+}
+
+// Register adds a problem type to the registry.  A type that is already
+// registered is replaced.
+func (reg *Registry) Register(info TypeInfo) error {
+	if strings.TrimSpace(info.Type) == "" || info.Type == "about:blank" {
+		return New(500, "A problem type URI is required to register a type")
+	}
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.types[info.Type] = info
+	reg.updateLocales()
+	return nil
+}
+
+// updateLocales collects the languages of the titles of the types.  The
+// caller holds the write lock.
+func (reg *Registry) updateLocales() {
+	seen := make(map[string]bool)
+	var locales []string
+	for _, info := range reg.types {
+		for locale := range info.Titles {
+			if locale = normalizeLocale(locale); !seen[locale] {
+				seen[locale] = true
+				locales = append(locales, locale)
+			}
+		}
+	}
+	sort.Strings(locales)
+	reg.locales = locales
+}
+
+// Locales lists the languages of the localized titles of the registered
+// types, sorted
+func (reg *Registry) Locales() []string {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	return append([]string(nil), reg.locales...)
+}
+
+// Lookup returns the registered information for the problem type
+func (reg *Registry) Lookup(typeURI string) (TypeInfo, bool) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	info, ok := reg.types[typeURI]
+	return info, ok
+}
+
+// Types returns all registered problem types sorted by type URI
+func (reg *Registry) Types() []TypeInfo {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	types := make([]TypeInfo, 0, len(reg.types))
+	for _, info := range reg.types {
+		types = append(types, info)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].Type < types[j].Type
+	})
+	return types
+}
+
+// Version returns the version of the most recently loaded type file
+func (reg *Registry) Version() string {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	return reg.version
+}
+
+// RegisterType adds a problem type to the DefaultRegistry
+func RegisterType(info TypeInfo) error {
+	return DefaultRegistry.Register(info)
+}
+
+// LookupType returns the problem type from the DefaultRegistry
+func LookupType(typeURI string) (TypeInfo, bool) {
+	return DefaultRegistry.Lookup(typeURI)
+}
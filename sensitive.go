@@ -0,0 +1,78 @@
+package problems
+
+import (
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// SensitiveTag is the struct tag option that marks a field as sensitive:
+//
+//	Password string `json:"password" problem:"sensitive"`
+const SensitiveTag = "sensitive"
+
+// MaskedValue replaces the value of a sensitive issue when it is
+// serialized.  Set it to nil to omit the value.
+var MaskedValue interface{} = "******"
+
+var sensitiveNames = struct {
+	sync.RWMutex
+	patterns []string
+}{patterns: []string{
+	"*password*", "*passwd*", "*passphrase*", "*secret*", "*token*", "*apikey*",
+	"*cardnumber*", "*creditcard*", "pan", "cvv", "cvc", "pin", "ssn", "authorization", "cookie",
+}}
+
+// RegisterSensitiveName adds a path.Match pattern of the names of
+// sensitive fields, eg. "*iban*".  Names are matched by their last
+// segment, in lower case and without underscores or hyphens, so
+// card_number, cardNumber and card-number all match "*cardnumber*".
+func RegisterSensitiveName(pattern string) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return Errorf(500, "Invalid sensitive name pattern %q: %v", pattern, err)
+	}
+	sensitiveNames.Lock()
+	defer sensitiveNames.Unlock()
+	sensitiveNames.patterns = append(sensitiveNames.patterns, pattern)
+	return nil
+}
+
+// IsSensitiveName reports whether a field or parameter name, such as
+// user.password or X-Api-Key, matches a sensitive name pattern
+func IsSensitiveName(name string) bool {
+	if i := strings.LastIndexAny(name, ".["); i >= 0 {
+		if name[i] == '[' && i > 0 && strings.HasSuffix(name, "]") {
+			// an element of a sensitive slice or map, eg. pins[0]
+			return IsSensitiveName(name[:i])
+		}
+		name = name[i+1:]
+	}
+	name = strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(name))
+	sensitiveNames.RLock()
+	defer sensitiveNames.RUnlock()
+	for _, pattern := range sensitiveNames.patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSensitiveField reports whether a struct field has the sensitive
+// option in its problem tag
+func IsSensitiveField(fld reflect.StructField) bool {
+	for _, option := range strings.Split(fld.Tag.Get("problem"), ",") {
+		if strings.TrimSpace(option) == SensitiveTag {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSensitive reports whether the value of the issue is masked when it is
+// serialized, because it was flagged Sensitive or its name matches a
+// sensitive name pattern.  The Value itself is kept for use on the server.
+func (issue Issue) IsSensitive() bool {
+	return issue.Sensitive || IsSensitiveName(issue.Name)
+}
@@ -0,0 +1,72 @@
+package problems
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestIsSensitiveName(t *testing.T) {
+	tests := map[string]bool{
+		"password":           true,
+		"user.newPassword":   true,
+		"card_number":        true,
+		"payment.cardNumber": true,
+		"X-Api-Key":          true,
+		"cards[0].cvv":       true,
+		"pin":                true,
+		"shipping":           false,
+		"email":              false,
+		"passwordHint[0]":    true,
+		"items[3].sku":       false,
+	}
+	for name, want := range tests {
+		if got := IsSensitiveName(name); got != want {
+			t.Errorf("IsSensitiveName(%q) = %v, want %v", name, got, want)
+		}
+	}
+	if err := RegisterSensitiveName("["); err == nil {
+		t.Error("RegisterSensitiveName() accepted an invalid pattern")
+	}
+}
+
+func TestIssue_Masking(t *testing.T) {
+	issues := []Issue{
+		{Name: "password", Value: "hunter2"},
+		{Name: "nickname", Value: "hunter3", Sensitive: true},
+		{Name: "email", Value: "nobody"},
+	}
+	data, err := json.Marshal(issues)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if strings.Contains(string(data), "hunter") || !strings.Contains(string(data), `"value":"nobody"`) {
+		t.Errorf("Marshal() = %s", data)
+	}
+	if issues[0].Value != "hunter2" {
+		t.Errorf("Marshal() changed the value to %v", issues[0].Value)
+	}
+
+	defer func(mask interface{}) { MaskedValue = mask }(MaskedValue)
+	MaskedValue = nil
+	data, _ = json.Marshal(issues[0])
+	if string(data) != `{"name":"password"}` {
+		t.Errorf("Marshal() without a mask = %s", data)
+	}
+}
+
+func TestDecodeJSON_Sensitive(t *testing.T) {
+	var dst struct {
+		PIN int `json:"code" problem:"sensitive"`
+	}
+	err := DecodeJSON(httptest.NewRecorder(), jsonRequest(`{"code": "1234"}`), &dst, WithValidator(nil))
+	prob, ok := err.(*Problem)
+	if !ok {
+		t.Fatalf("DecodeJSON() error = %v, want a problem", err)
+	}
+	data, _ := json.Marshal(prob)
+	if strings.Contains(string(data), "1234") || !strings.Contains(string(data), `"value":"******"`) {
+		t.Errorf("DecodeJSON() = %s", data)
+	}
+}
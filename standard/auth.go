@@ -0,0 +1,135 @@
+package standard
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"tjdavis.dev/problems"
+)
+
+// ErrTokenExpired is returned, or wrapped, by a TokenVerifier for an
+// access token that has expired
+var ErrTokenExpired = errors.New("access token expired")
+
+// Token is a verified access token
+type Token struct {
+	// Claims are the claims of the token, eg. sub and client_id
+	Claims map[string]interface{}
+	// Expiry is when the token expires; zero if it doesn't
+	Expiry time.Time
+	// Scopes are the scopes granted to the token
+	Scopes []string
+}
+
+// HasScope reports whether the scope was granted to the token
+func (tok *Token) HasScope(scope string) bool {
+	for _, granted := range tok.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// TokenVerifier verifies a Bearer access token, eg. by checking the
+// signature of a JWT or by introspection.  An expired token is reported
+// with ErrTokenExpired or an Expiry in the past.  A problem is rendered as
+// is, so a verifier can report that it is unavailable; any other error
+// means the token is invalid.
+type TokenVerifier interface {
+	VerifyToken(ctx context.Context, token string) (*Token, error)
+}
+
+// TokenVerifierFunc is a function used as a TokenVerifier
+type TokenVerifierFunc func(ctx context.Context, token string) (*Token, error)
+
+// VerifyToken calls f(ctx, token)
+func (f TokenVerifierFunc) VerifyToken(ctx context.Context, token string) (*Token, error) {
+	return f(ctx, token)
+}
+
+type tokenKey struct{}
+
+// TokenFromContext returns the token verified by Authenticate
+func TokenFromContext(ctx context.Context) (*Token, bool) {
+	tok, ok := ctx.Value(tokenKey{}).(*Token)
+	return tok, ok
+}
+
+// bearerToken returns the token of an `Authorization: Bearer` header
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// Authenticate is middleware that requires a valid Bearer access token.
+// The token is verified by the verifier and made available to the handler
+// with TokenFromContext.  Requests are rejected with the problem of
+// GetNoAccessResponse, GetExpiredTokenResponse or GetInvalidTokenResponse.
+func Authenticate(verifier TokenVerifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			raw, ok := bearerToken(r)
+			if !ok {
+				_ = GetNoAccessResponse().Render(w, r)
+				return
+			}
+			tok, err := verifier.VerifyToken(r.Context(), raw)
+			var prob *problems.Problem
+			switch {
+			case errors.Is(err, ErrTokenExpired):
+				_ = GetExpiredTokenResponse().Render(w, r)
+				return
+			case errors.As(err, &prob):
+				_ = prob.Render(w, r)
+				return
+			case err != nil || tok == nil:
+				_ = GetInvalidTokenResponse().Render(w, r)
+				return
+			case !tok.Expiry.IsZero() && !time.Now().Before(tok.Expiry):
+				_ = GetExpiredTokenResponse().Render(w, r)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), tokenKey{}, tok)))
+		})
+	}
+}
+
+// RequireScopes is middleware that requires the token verified by
+// Authenticate to have every scope.  Requests are rejected with the
+// problem of GetMissingScopeResponse listing the missing scopes, or of
+// GetNoAccessResponse without a token.
+func RequireScopes(scopes ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tok, ok := TokenFromContext(r.Context())
+			if !ok {
+				_ = GetNoAccessResponse().Render(w, r)
+				return
+			}
+			if missing := MissingScopes(tok, scopes...); len(missing) > 0 {
+				_ = GetMissingScopeResponse(missing).Render(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// MissingScopes lists the required scopes that weren't granted to the token
+func MissingScopes(tok *Token, required ...string) []string {
+	var missing []string
+	for _, scope := range required {
+		if !tok.HasScope(scope) {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
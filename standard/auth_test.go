@@ -0,0 +1,98 @@
+package standard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"tjdavis.dev/problems"
+)
+
+func testVerifier(ctx context.Context, token string) (*Token, error) {
+	switch token {
+	case "reader":
+		return &Token{Scopes: []string{"users:read"}, Expiry: time.Now().Add(time.Hour)}, nil
+	case "stale":
+		return &Token{Scopes: []string{"users:read"}, Expiry: time.Now().Add(-time.Minute)}, nil
+	case "expired":
+		return nil, fmt.Errorf("jwt: %w", ErrTokenExpired)
+	case "unavailable":
+		return nil, GetServiceUnavailableResponse(time.Minute)
+	default:
+		return nil, errors.New("bad signature")
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	handler := Authenticate(TokenVerifierFunc(testVerifier))(
+		RequireScopes("users:read", "users:write")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})))
+	tests := []struct {
+		name          string
+		authorization string
+		status        int
+		typeURI       string
+		scopes        []string
+	}{
+		{"Test no token", "", 401, TypeNoAccessToken, nil},
+		{"Test basic auth", "Basic dXNlcjpwYXNz", 401, TypeNoAccessToken, nil},
+		{"Test invalid token", "Bearer forged", 401, TypeInvalidToken, nil},
+		{"Test expired error", "Bearer expired", 401, TypeTokenExpired, nil},
+		{"Test expired token", "bearer stale", 401, TypeTokenExpired, nil},
+		{"Test verifier problem", "Bearer unavailable", 503, TypeServiceUnavailable, nil},
+		{"Test missing scope", "Bearer reader", 403, TypeMissingScope, []string{"users:write"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/users", nil)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			var prob problems.Problem
+			if err := json.Unmarshal(w.Body.Bytes(), &prob); err != nil {
+				t.Fatalf("response %q: %v", w.Body, err)
+			}
+			if w.Code != tt.status || prob.Type != tt.typeURI {
+				t.Errorf("response = %d %s, want %d %s", w.Code, prob.Type, tt.status, tt.typeURI)
+			}
+			if w.Code == 401 && w.Header().Get("WWW-Authenticate") == "" {
+				t.Error("response has no WWW-Authenticate header")
+			}
+			if tt.scopes != nil {
+				scopes, _ := prob.Get("requiredscopes").([]interface{})
+				if len(scopes) != len(tt.scopes) || scopes[0] != tt.scopes[0] {
+					t.Errorf("requiredScopes = %v, want %v", scopes, tt.scopes)
+				}
+			}
+		})
+	}
+}
+
+func TestRequireScopes(t *testing.T) {
+	var seen *Token
+	handler := RequireScopes("users:read")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = TokenFromContext(r.Context())
+	}))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/users", nil))
+	if w.Code != 401 {
+		t.Errorf("RequireScopes() without a token = %d, want 401", w.Code)
+	}
+
+	handler = Authenticate(TokenVerifierFunc(testVerifier))(handler)
+	r := httptest.NewRequest("GET", "/users", nil)
+	r.Header.Set("Authorization", "Bearer reader")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != 200 || seen == nil || !seen.HasScope("users:read") {
+		t.Errorf("RequireScopes() = %d, token %+v", w.Code, seen)
+	}
+}
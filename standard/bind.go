@@ -0,0 +1,256 @@
+package standard
+
+import (
+	"encoding"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"tjdavis.dev/problems"
+)
+
+// bindTags are the struct tags naming the request parameter a field is
+// bound to, and the issue location they are reported in
+var bindTags = []string{"query", "path", "header"}
+
+// FieldName is the validator TagNameFunc registered by NewValidation.  A
+// field bound with a query, path or header tag is named by its parameter,
+// other fields by their json name.
+func FieldName(fld reflect.StructField) string {
+	for _, tag := range bindTags {
+		if name := fld.Tag.Get(tag); name != "" && name != "-" {
+			return name
+		}
+	}
+	return JSONFieldName(fld)
+}
+
+// binding is a struct field bound to a request parameter
+type binding struct {
+	index     []int
+	location  string
+	name      string
+	sensitive bool
+}
+
+// bindings lists the fields of t with a query, path or header tag keyed by
+// the validator's struct namespace below the root, eg. Paging.Limit.  The
+// fields of embedded structs are included.
+func bindings(t reflect.Type, prefix string, index []int) map[string]binding {
+	fields := make(map[string]binding)
+	for i := 0; i < t.NumField(); i++ {
+		fld := t.Field(i)
+		fieldIndex := append(append([]int(nil), index...), i)
+		if fld.Anonymous && fld.Type.Kind() == reflect.Struct {
+			for key, b := range bindings(fld.Type, prefix+fld.Name+".", fieldIndex) {
+				fields[key] = b
+			}
+			continue
+		}
+		if fld.PkgPath != "" {
+			continue
+		}
+		for _, tag := range bindTags {
+			if name := fld.Tag.Get(tag); name != "" && name != "-" {
+				fields[prefix+fld.Name] = binding{
+					index:     fieldIndex,
+					location:  tag,
+					name:      name,
+					sensitive: problems.IsSensitiveField(fld),
+				}
+				break
+			}
+		}
+	}
+	return fields
+}
+
+// values returns the values of the parameter and whether it was sent
+func (b binding) values(r *http.Request, path func(string) string) ([]string, bool) {
+	switch b.location {
+	case "query":
+		values, ok := r.URL.Query()[b.name]
+		return values, ok
+	case "header":
+		values := r.Header.Values(b.name)
+		return values, len(values) > 0
+	default:
+		if path == nil {
+			return nil, false
+		}
+		value := path(b.name)
+		return []string{value}, value != ""
+	}
+}
+
+var (
+	textUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	durationType    = reflect.TypeOf(time.Duration(0))
+)
+
+// setValues converts the parameter values to the type of the field.  A
+// slice receives every value, other types the first.
+func setValues(field reflect.Value, values []string) bool {
+	if field.Kind() == reflect.Slice && !field.Addr().Type().Implements(textUnmarshaler) {
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, value := range values {
+			if !setValue(slice.Index(i), value) {
+				return false
+			}
+		}
+		field.Set(slice)
+		return true
+	}
+	return setValue(field, values[0])
+}
+
+func setValue(field reflect.Value, value string) bool {
+	if field.Kind() == reflect.Ptr {
+		elem := reflect.New(field.Type().Elem())
+		if !setValue(elem.Elem(), value) {
+			return false
+		}
+		field.Set(elem)
+		return true
+	}
+	if field.Addr().Type().Implements(textUnmarshaler) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value)) == nil
+	}
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return false
+		}
+		field.SetInt(int64(d))
+		return true
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return false
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return false
+		}
+		field.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return false
+		}
+		field.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return false
+		}
+		field.SetFloat(f)
+	default:
+		return false
+	}
+	return true
+}
+
+// Bind populates dst, a pointer to a struct, from the request parameters
+// named by the field tags and validates it:
+//
+//	type ListParams struct {
+//		Limit  int    `query:"limit" validate:"min=1,max=100"`
+//		Tenant string `header:"X-Tenant" validate:"required"`
+//		ID     string `path:"id"`
+//	}
+//
+// path returns the value of a path parameter, eg. http.Request.PathValue or
+// the router's equivalent; it may be nil.  Parameters that aren't sent
+// leave the field unchanged.
+//
+// Values that can't be converted and validation errors are reported as
+// schema violation issues of one input validation problem, each with the
+// location and name of the parameter and described in the language of the
+// request.  nil is returned when the parameters are valid.
+func (v *Validation) Bind(r *http.Request, dst interface{}, path func(name string) string) error {
+	target := reflect.ValueOf(dst)
+	if target.Kind() != reflect.Ptr || target.Elem().Kind() != reflect.Struct {
+		return problems.Errorf(500, "Bind requires a pointer to a struct, not %T", dst)
+	}
+	target = target.Elem()
+	fields := bindings(target.Type(), "", nil)
+	language := r.Header.Get("Accept-Language")
+	locale := problems.NegotiateLocale(language, problems.AvailableLocales(), problems.DefaultLocale)
+
+	var issues []problems.Issue
+	invalid := make(map[string]bool)
+	for key, b := range fields {
+		values, ok := b.values(r, path)
+		if !ok {
+			continue
+		}
+		if !setValues(target.FieldByIndex(b.index), values) {
+			invalid[key] = true
+			var value interface{} = values[0]
+			if len(values) > 1 {
+				value = values
+			}
+			issue := problems.Issue{
+				Type:      TypeSchemaViolation,
+				In:        b.location,
+				Name:      b.name,
+				Value:     value,
+				Sensitive: b.sensitive,
+			}
+			key, params := DetailInvalidValue, map[string]interface{}{"name": b.name, "value": value}
+			if issue.IsSensitive() {
+				// the detail mustn't reveal the masked value
+				params["value"] = problems.MaskedValue
+				if problems.MaskedValue == nil {
+					key, params = DetailInvalidValueHidden, map[string]interface{}{"name": b.name}
+				}
+			}
+			issue.SetDetailMessage(key, params)
+			issues = append(issues, issue.Localize(locale, nil))
+		}
+	}
+
+	err := v.Validate.Struct(target.Interface())
+	if errs, ok := err.(validator.ValidationErrors); ok {
+		trans := v.Translator(language)
+		for _, fe := range errs {
+			key := fe.StructNamespace()
+			if i := strings.IndexByte(key, '.'); i >= 0 {
+				key = key[i+1:]
+			}
+			if invalid[key] {
+				continue
+			}
+			issue := v.Issue(fe, trans)
+			if b, ok := fields[key]; ok {
+				issue.In = b.location
+				issue.Name = b.name
+				issue.Pointer = ""
+			}
+			issues = append(issues, issue)
+		}
+	} else if prob := v.FromError(language, err); prob != nil {
+		return prob
+	}
+	if len(issues) == 0 {
+		return nil
+	}
+	problems.SortIssues(issues)
+	return problems.InputValidation(issues...)
+}
+
+// Bind populates and validates dst from the request parameters using the
+// DefaultValidation; see Validation.Bind.
+func Bind(r *http.Request, dst interface{}, path func(name string) string) error {
+	return DefaultValidation.Bind(r, dst, path)
+}
@@ -0,0 +1,137 @@
+package standard
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"tjdavis.dev/problems"
+)
+
+type testPaging struct {
+	Limit  int `query:"limit" validate:"min=1,max=100"`
+	Offset int `query:"offset" validate:"min=0"`
+}
+
+type testListParams struct {
+	testPaging
+	Tags    []string      `query:"tag"`
+	Timeout time.Duration `query:"timeout"`
+	Since   *time.Time    `query:"since"`
+	Tenant  string        `header:"X-Tenant" validate:"required"`
+	ID      string        `path:"id" validate:"uuid"`
+}
+
+func TestBind(t *testing.T) {
+	r := httptest.NewRequest("GET", "/users/bdd4ca73-2c2e-4c9f-a1b8-8b6f1b5f8e55?limit=20&tag=a&tag=b&timeout=5s&since=2024-01-02T03:04:05Z", nil)
+	r.Header.Set("X-Tenant", "acme")
+	path := func(name string) string {
+		return map[string]string{"id": "bdd4ca73-2c2e-4c9f-a1b8-8b6f1b5f8e55"}[name]
+	}
+	var params testListParams
+	if err := Bind(r, &params, path); err != nil {
+		t.Fatalf("Bind() error = %v", err)
+	}
+	if params.Limit != 20 || len(params.Tags) != 2 || params.Timeout != 5*time.Second ||
+		params.Since == nil || params.Since.Year() != 2024 || params.Tenant != "acme" || params.ID == "" {
+		t.Errorf("Bind() params = %+v", params)
+	}
+}
+
+func TestBind_Issues(t *testing.T) {
+	r := httptest.NewRequest("GET", "/users/42?limit=abc&offset=-1", nil)
+	r.Header.Set("Accept-Language", "fr")
+	path := func(name string) string { return "42" }
+	params := testListParams{testPaging: testPaging{Limit: 10}}
+	err := Bind(r, &params, path)
+	prob, ok := err.(*problems.Problem)
+	if !ok {
+		t.Fatalf("Bind() error = %v, want a problem", err)
+	}
+	want := []struct{ in, name, detail string }{
+		{"header", "X-Tenant", "X-Tenant est un champ obligatoire"},
+		{"path", "id", "id doit être un UUID valid"},
+		{"query", "limit", "La valeur abc de limit n'est pas valide"},
+		{"query", "offset", "offset doit être égal à 0 ou plus"},
+	}
+	issues := prob.Issues()
+	if len(issues) != len(want) {
+		t.Fatalf("Bind() issues = %d, want %d", len(issues), len(want))
+	}
+	for i, issue := range issues {
+		if issue.Type != TypeSchemaViolation || issue.In != want[i].in ||
+			issue.Name != want[i].name || issue.Detail != want[i].detail {
+			t.Errorf("Bind() issue %d = %v %v %v %q", i, issue.Type, issue.In, issue.Name, issue.Detail)
+		}
+	}
+}
+
+func TestBind_InvalidDuration(t *testing.T) {
+	r := httptest.NewRequest("GET", "/users/bdd4ca73-2c2e-4c9f-a1b8-8b6f1b5f8e55?limit=20&timeout=soon", nil)
+	r.Header.Set("X-Tenant", "acme")
+	path := func(name string) string { return "bdd4ca73-2c2e-4c9f-a1b8-8b6f1b5f8e55" }
+	params := testListParams{Timeout: 30 * time.Second}
+	err := Bind(r, &params, path)
+	prob, ok := err.(*problems.Problem)
+	if !ok || len(prob.Issues()) != 1 || prob.Issues()[0].Name != "timeout" {
+		t.Fatalf("Bind() error = %v, want a timeout issue", err)
+	}
+	if params.Timeout != 30*time.Second {
+		t.Errorf("Bind() timeout = %v, want the default 30s", params.Timeout)
+	}
+}
+
+func TestBind_NotStruct(t *testing.T) {
+	var limit int
+	if err := Bind(httptest.NewRequest("GET", "/", nil), &limit, nil); err == nil {
+		t.Error("Bind() accepted a pointer to an int")
+	}
+}
+
+type testLogin struct {
+	User string `json:"user" validate:"required"`
+	Code string `json:"code" validate:"len=6" problem:"sensitive"`
+	PIN  int    `query:"pin"`
+}
+
+func TestValidation_Sensitive(t *testing.T) {
+	v := testValidation(t)
+	login := testLogin{Code: "12345"}
+	err := v.ValidateRequest(httptest.NewRequest("POST", "/login", nil), &login)
+	prob, ok := err.(*problems.Problem)
+	if !ok {
+		t.Fatalf("ValidateRequest() error = %v, want a problem", err)
+	}
+	for _, issue := range prob.Issues() {
+		if issue.IsSensitive() != (issue.Name == "code") {
+			t.Errorf("ValidateRequest() issue %v sensitive = %v", issue.Name, issue.IsSensitive())
+		}
+		if issue.Name == "code" && issue.Value != "12345" {
+			t.Errorf("ValidateRequest() lost the value of %v: %v", issue.Name, issue.Value)
+		}
+	}
+
+	err = v.Bind(httptest.NewRequest("GET", "/login?pin=12a4", nil), &testLogin{User: "a", Code: "123456"}, nil)
+	data, _ := json.Marshal(err)
+	if strings.Contains(string(data), "12a4") {
+		t.Errorf("Bind() = %s, want the pin masked", data)
+	}
+}
+
+func TestBind_IssueMessages(t *testing.T) {
+	defer func(masked interface{}) { problems.MaskedValue = masked }(problems.MaskedValue)
+	problems.MaskedValue = nil
+	err := Bind(httptest.NewRequest("GET", "/login?pin=12a4", nil), &testLogin{User: "a", Code: "123456"}, nil)
+	prob, ok := err.(*problems.Problem)
+	if !ok || len(prob.Issues()) != 1 {
+		t.Fatalf("Bind() error = %v, want a pin issue", err)
+	}
+	if issue := prob.Issues()[0]; issue.Detail != "The value of pin is invalid" || issue.DetailKey() != DetailInvalidValueHidden {
+		t.Errorf("Bind() issue detail = %q", issue.Detail)
+	}
+	if issue := prob.Localize("fr", nil).Issues()[0]; issue.Detail != "La valeur de pin n'est pas valide" {
+		t.Errorf("Localize() issue detail = %q", issue.Detail)
+	}
+}
@@ -0,0 +1,49 @@
+package standard
+
+import (
+	"strings"
+)
+
+// Realm is the realm of the Bearer challenges sent with the standard 401
+// and 403 problems.  It is omitted when empty.
+var Realm string
+
+// Challenge is an RFC 6750 Bearer challenge sent in the WWW-Authenticate
+// header of an authentication problem
+type Challenge struct {
+	Realm string
+	// Error is the RFC 6750 error code: invalid_request, invalid_token or
+	// insufficient_scope.  It is omitted when the request had no token.
+	Error            string
+	ErrorDescription string
+	ErrorURI         string
+	// Scope lists the scopes required to access the resource
+	Scope []string
+}
+
+// String formats the challenge as a WWW-Authenticate header value, eg.
+//
+//	Bearer realm="example", error="invalid_token", error_description="The access token expired"
+func (c Challenge) String() string {
+	var params []string
+	for _, param := range []struct{ name, value string }{
+		{"realm", c.Realm},
+		{"scope", strings.Join(c.Scope, " ")},
+		{"error", c.Error},
+		{"error_description", c.ErrorDescription},
+		{"error_uri", c.ErrorURI},
+	} {
+		if param.value != "" {
+			params = append(params, param.name+"="+quote(param.value))
+		}
+	}
+	if len(params) == 0 {
+		return "Bearer"
+	}
+	return "Bearer " + strings.Join(params, ", ")
+}
+
+// quote formats an RFC 7235 quoted-string
+func quote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
@@ -0,0 +1,59 @@
+package standard
+
+import (
+	"testing"
+	"time"
+
+	"tjdavis.dev/problems"
+)
+
+func TestChallenge_String(t *testing.T) {
+	tests := []struct {
+		challenge Challenge
+		want      string
+	}{
+		{Challenge{}, "Bearer"},
+		{Challenge{Realm: "example"}, `Bearer realm="example"`},
+		{Challenge{Error: "invalid_token", ErrorDescription: `The "token" expired`},
+			`Bearer error="invalid_token", error_description="The \"token\" expired"`},
+		{Challenge{Realm: "example", Error: "insufficient_scope", Scope: []string{"users:read", "users:write"}},
+			`Bearer realm="example", scope="users:read users:write", error="insufficient_scope"`},
+	}
+	for _, tt := range tests {
+		if got := tt.challenge.String(); got != tt.want {
+			t.Errorf("String() = %v, want %v", got, tt.want)
+		}
+	}
+}
+
+func TestResponseHeaders(t *testing.T) {
+	defer func(realm string) { Realm = realm }(Realm)
+	Realm = "api"
+	tests := []struct {
+		name   string
+		prob   *problems.Problem
+		header string
+		want   string
+	}{
+		{"Test no access", GetNoAccessResponse(), "WWW-Authenticate", `Bearer realm="api"`},
+		{"Test invalid token", GetInvalidTokenResponse(), "WWW-Authenticate",
+			`Bearer realm="api", error="invalid_token", error_description="The access token is invalid"`},
+		{"Test expired token", GetExpiredTokenResponse(), "WWW-Authenticate",
+			`Bearer realm="api", error="invalid_token", error_description="The access token expired"`},
+		{"Test missing scope", GetMissingScopeResponse([]string{"users:read"}), "WWW-Authenticate",
+			`Bearer realm="api", scope="users:read", error="insufficient_scope"`},
+		{"Test method not allowed", GetMethodNotAllowedResponse("PUT", "GET", "HEAD"), "Allow", "GET, HEAD"},
+		{"Test unsupported media type", GetUnsupportedMediaTypeResponse("application/json"), "Accept", "application/json"},
+		{"Test unsupported patch", GetUnsupportedPatchResponse("application/merge-patch+json"), "Accept-Patch",
+			"application/merge-patch+json"},
+		{"Test too many requests", GetTooManyRequestsResponse(time.Minute), "Retry-After", "60"},
+		{"Test service unavailable", GetServiceUnavailableResponse(90 * time.Second), "Retry-After", "90"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.prob.Header().Get(tt.header); got != tt.want {
+				t.Errorf("%s = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package standard
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+
+	"tjdavis.dev/problems"
+)
+
+// statusTypes are the problem types of the statuses InterceptErrors
+// converts; other statuses are about:blank problems
+var statusTypes = map[int]string{
+	http.StatusBadRequest:            TypeBadRequest,
+	http.StatusNotFound:              TypeNotFound,
+	http.StatusNotAcceptable:         TypeNotAcceptable,
+	http.StatusConflict:              TypeConflict,
+	http.StatusGone:                  TypeGone,
+	http.StatusPreconditionFailed:    TypePreconditionFailed,
+	http.StatusRequestEntityTooLarge: TypePayloadTooLarge,
+	http.StatusUnsupportedMediaType:  TypeUnsupportedMediaType,
+	http.StatusUnprocessableEntity:   TypeUnprocessableEntity,
+	http.StatusPreconditionRequired:  TypePreconditionRequired,
+	http.StatusTooManyRequests:       TypeTooManyRequests,
+	http.StatusInternalServerError:   TypeInternalServerError,
+	http.StatusNotImplemented:        TypeNotImplemented,
+	http.StatusBadGateway:            TypeBadGateway,
+	http.StatusServiceUnavailable:    TypeServiceUnavailable,
+	http.StatusGatewayTimeout:        TypeGatewayTimeout,
+}
+
+// maxInterceptedBody is how much of an intercepted body is kept for the
+// detail
+const maxInterceptedBody = 512
+
+// InterceptErrors is middleware that replaces the 4xx and 5xx responses
+// of handlers that don't render problems, such as http.FileServer,
+// http.Error and the 404 and 405 of http.ServeMux, with a problem of the
+// same status.  The problem has the standard type of the status, and a
+// 405 is the problem of GetMethodNotAllowedResponse.  The headers set by
+// the handler, eg. Allow or Retry-After, are kept.
+//
+// The detail of a 4xx problem is the plain text message of the handler
+// when it is a single line; 5xx messages aren't revealed.  Other bodies,
+// including JSON error bodies that aren't problems, are dropped: only
+// their status and headers are kept.  Responses that
+// are already problems and successful responses are passed through, as
+// are http.Flusher, http.Hijacker and io.ReaderFrom.
+func InterceptErrors(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		iw := &interceptWriter{ResponseWriter: w}
+		next.ServeHTTP(iw, r)
+		if iw.status != 0 {
+			_ = interceptedProblem(r, iw.status, iw.Header(), iw.body.String()).Render(w, r)
+		}
+	})
+}
+
+// interceptedProblem creates the problem replacing an error response.  r
+// may be nil, eg. for the response of a proxy without its request.
+func interceptedProblem(r *http.Request, status int, header http.Header, body string) *problems.Problem {
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	header.Del("Content-Length")
+	header.Del("Content-Type")
+	if status == http.StatusMethodNotAllowed {
+		var allowed []string
+		for _, method := range strings.Split(header.Get("Allow"), ",") {
+			if method = strings.TrimSpace(method); method != "" {
+				allowed = append(allowed, method)
+			}
+		}
+		method := ""
+		if r != nil {
+			method = r.Method
+		}
+		prob := GetMethodNotAllowedResponse(method, allowed...)
+		if len(allowed) == 0 {
+			// the handler didn't say which methods are allowed
+			prob.Header().Del("Allow")
+		}
+		return prob
+	}
+	prob := statusProblem(status)
+	body = strings.TrimSpace(body)
+	if status < http.StatusInternalServerError && body != "" && !strings.ContainsAny(body, "\r\n<") &&
+		len(body) < maxInterceptedBody && (mediaType == "" || mediaType == "text/plain") {
+		prob.Detail = body
+	}
+	return prob
+}
+
+// statusProblem creates the problem of a status with the standard type of
+// the status and its text as the title and detail
+func statusProblem(status int) *problems.Problem {
+	prob := problems.New(status, http.StatusText(status))
+	prob.Title = http.StatusText(status)
+	if typeURI, ok := statusTypes[status]; ok {
+		_ = prob.Set("Type", typeURI)
+	}
+	return prob
+}
+
+// isProblemMediaType reports whether the content type is a problem
+func isProblemMediaType(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == problems.ProblemMediaType || mediaType == problems.ProblemXMLMediaType
+}
+
+// interceptWriter holds back the error responses of a handler run by
+// InterceptErrors
+type interceptWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+	// status is the status of the intercepted response, 0 if it isn't
+	status int
+	body   bytes.Buffer
+}
+
+func (iw *interceptWriter) WriteHeader(code int) {
+	if iw.wroteHeader {
+		return
+	}
+	if code >= http.StatusBadRequest && !isProblemMediaType(iw.Header().Get("Content-Type")) {
+		iw.wroteHeader = true
+		iw.status = code
+		return
+	}
+	// informational responses are followed by the final one
+	iw.wroteHeader = code >= http.StatusOK
+	iw.ResponseWriter.WriteHeader(code)
+}
+
+func (iw *interceptWriter) Write(p []byte) (int, error) {
+	if !iw.wroteHeader {
+		iw.WriteHeader(http.StatusOK)
+	}
+	if iw.status != 0 {
+		if room := maxInterceptedBody - iw.body.Len(); room > 0 {
+			if len(p) < room {
+				room = len(p)
+			}
+			iw.body.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return iw.ResponseWriter.Write(p)
+}
+
+// Flush flushes the response unless it is intercepted
+func (iw *interceptWriter) Flush() {
+	if iw.status != 0 {
+		return
+	}
+	if f, ok := iw.ResponseWriter.(http.Flusher); ok {
+		if !iw.wroteHeader {
+			iw.WriteHeader(http.StatusOK)
+		}
+		f.Flush()
+	}
+}
+
+// Hijack hijacks the connection of the underlying ResponseWriter
+func (iw *interceptWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := iw.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
+
+// ReadFrom uses the io.ReaderFrom of the underlying ResponseWriter, eg.
+// for sendfile, unless the response is intercepted
+func (iw *interceptWriter) ReadFrom(src io.Reader) (int64, error) {
+	if !iw.wroteHeader {
+		iw.WriteHeader(http.StatusOK)
+	}
+	if rf, ok := iw.ResponseWriter.(io.ReaderFrom); ok && iw.status == 0 {
+		return rf.ReadFrom(src)
+	}
+	return io.Copy(writerOnly{iw}, src)
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController
+func (iw *interceptWriter) Unwrap() http.ResponseWriter {
+	return iw.ResponseWriter
+}
+
+// writerOnly hides the ReadFrom method so io.Copy doesn't call it again
+type writerOnly struct {
+	io.Writer
+}
@@ -0,0 +1,140 @@
+package standard
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"tjdavis.dev/problems"
+)
+
+func TestInterceptErrors(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/items", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		_, _ = io.WriteString(w, "items")
+	})
+	mux.HandleFunc("/invalid", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "id must be a number", http.StatusBadRequest)
+	})
+	mux.HandleFunc("/fail", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "pq: connection refused", http.StatusInternalServerError)
+	})
+	mux.HandleFunc("/busy", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	mux.HandleFunc("/problem", func(w http.ResponseWriter, r *http.Request) {
+		_ = GetConflictResponse("Already exists").Render(w, r)
+	})
+	mux.Handle("/files/", http.StripPrefix("/files", http.FileServer(http.FS(fstest.MapFS{
+		"a.txt": {Data: []byte("file a")},
+	}))))
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+		typ    string
+		detail string
+		header string
+		value  string
+	}{
+		{"Test success", "GET", "/items", http.StatusOK, "", "items", "", ""},
+		{"Test not found", "GET", "/nothing", http.StatusNotFound, TypeNotFound, "404 page not found", "", ""},
+		{"Test method not allowed", "DELETE", "/items", http.StatusMethodNotAllowed, TypeMethodNotAllowed, "", "Allow", "GET, HEAD"},
+		{"Test plain text error", "GET", "/invalid", http.StatusBadRequest, TypeBadRequest, "id must be a number", "", ""},
+		{"Test server error", "GET", "/fail", http.StatusInternalServerError, TypeInternalServerError, "Internal Server Error", "", ""},
+		{"Test headers kept", "GET", "/busy", http.StatusServiceUnavailable, TypeServiceUnavailable, "", "Retry-After", "30"},
+		{"Test problem", "GET", "/problem", http.StatusConflict, TypeConflict, "Already exists", "", ""},
+		{"Test file", "GET", "/files/a.txt", http.StatusOK, "", "file a", "", ""},
+		{"Test missing file", "GET", "/files/b.txt", http.StatusNotFound, TypeNotFound, "404 page not found", "", ""},
+	}
+	handler := InterceptErrors(mux)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if tt.header != "" && w.Header().Get(tt.header) != tt.value {
+				t.Errorf("%s = %q, want %q", tt.header, w.Header().Get(tt.header), tt.value)
+			}
+			if tt.typ == "" {
+				if w.Body.String() != tt.detail {
+					t.Errorf("body = %q, want %q", w.Body.String(), tt.detail)
+				}
+				return
+			}
+			if ct := w.Header().Get("Content-Type"); ct != problems.ProblemMediaType {
+				t.Errorf("Content-Type = %v, want %v", ct, problems.ProblemMediaType)
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("body %q: %v", w.Body.String(), err)
+			}
+			if body["type"] != tt.typ {
+				t.Errorf("type = %v, want %v", body["type"], tt.typ)
+			}
+			if tt.detail != "" && body["detail"] != tt.detail {
+				t.Errorf("detail = %v, want %v", body["detail"], tt.detail)
+			}
+		})
+	}
+}
+
+// fullWriter is a ResponseWriter with the optional interfaces
+type fullWriter struct {
+	*httptest.ResponseRecorder
+	readFrom bool
+}
+
+func (fw *fullWriter) ReadFrom(src io.Reader) (int64, error) {
+	fw.readFrom = true
+	return io.Copy(fw.ResponseRecorder, src)
+}
+
+func TestInterceptErrors_NoAllow(t *testing.T) {
+	w := httptest.NewRecorder()
+	InterceptErrors(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	})).ServeHTTP(w, httptest.NewRequest("POST", "/", nil))
+	if _, ok := w.Header()["Allow"]; ok || w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Allow = %q, status = %d, want no Allow header", w.Header().Values("Allow"), w.Code)
+	}
+}
+
+func TestInterceptErrors_Passthrough(t *testing.T) {
+	fw := &fullWriter{ResponseRecorder: httptest.NewRecorder()}
+	InterceptErrors(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := w.(http.Hijacker); !ok {
+			t.Error("ResponseWriter isn't an http.Hijacker")
+		}
+		_, _ = io.WriteString(w, "streamed ")
+		w.(http.Flusher).Flush()
+		_, _ = w.(io.ReaderFrom).ReadFrom(strings.NewReader("body"))
+	})).ServeHTTP(fw, httptest.NewRequest("GET", "/", nil))
+	if !fw.Flushed || !fw.readFrom || fw.Body.String() != "streamed body" {
+		t.Errorf("flushed = %v, readFrom = %v, body = %q", fw.Flushed, fw.readFrom, fw.Body.String())
+	}
+
+	fw = &fullWriter{ResponseRecorder: httptest.NewRecorder()}
+	InterceptErrors(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.(http.Flusher).Flush()
+		_, _ = w.(io.ReaderFrom).ReadFrom(strings.NewReader("not here"))
+	})).ServeHTTP(fw, httptest.NewRequest("GET", "/", nil))
+	if fw.Code != http.StatusNotFound || fw.readFrom || !strings.Contains(fw.Body.String(), `"detail":"not here"`) {
+		t.Errorf("intercepted response = %d %q", fw.Code, fw.Body.String())
+	}
+}
@@ -0,0 +1,70 @@
+package standard
+
+import (
+	"embed"
+
+	"tjdavis.dev/problems"
+)
+
+// Detail keys of the standard problems.  Translations for additional
+// languages can be added with problems.RegisterDetail or a message catalog.
+const (
+	DetailNoAccessToken     = "auth.noAccessToken"
+	DetailInvalidToken      = "auth.invalidToken"
+	DetailTokenExpired      = "auth.tokenExpired"
+	DetailMissingScope      = "auth.missingScope"
+	DetailMissingPermission = "auth.missingPermission"
+	DetailInputValidation   = problems.DetailInputValidation
+	// DetailInvalidValue has the name and value parameters
+	DetailInvalidValue = "input.invalidValue"
+	// DetailInvalidValueHidden has the name parameter, for a sensitive
+	// value that isn't shown
+	DetailInvalidValueHidden = "input.invalidValueHidden"
+	// DetailMissingResource has the type and value parameters
+	DetailMissingResource = "resource.missing"
+	// DetailResourceNotAssigned has the type and value parameters
+	DetailResourceNotAssigned = "resource.notAssigned"
+	// DetailMethodNotAllowed has the method and allowed parameters
+	DetailMethodNotAllowed = problems.DetailMethodNotAllowed
+	// DetailNotAcceptable has the supported parameter
+	DetailNotAcceptable = "http.notAcceptable"
+	DetailGone          = "http.gone"
+	// DetailPreconditionFailed is the default detail of a 412 problem
+	DetailPreconditionFailed = "http.preconditionFailed"
+	// DetailPayloadTooLarge has the limit parameter, in bytes
+	DetailPayloadTooLarge = "http.payloadTooLarge"
+	// DetailUnsupportedMediaType has the supported parameter
+	DetailUnsupportedMediaType = "http.unsupportedMediaType"
+	// DetailPreconditionRequired has the headers parameter
+	DetailPreconditionRequired = "http.preconditionRequired"
+	// DetailTooManyRequests has the retryAfter parameter, in seconds
+	DetailTooManyRequests = "http.tooManyRequests"
+	DetailNotImplemented  = "http.notImplemented"
+	DetailBadGateway      = "http.badGateway"
+	// DetailServiceUnavailable has the retryAfter parameter, in seconds
+	DetailServiceUnavailable = "http.serviceUnavailable"
+	DetailGatewayTimeout     = "http.gatewayTimeout"
+	// DetailUniqueViolation is the detail of a unique constraint violation
+	DetailUniqueViolation = "db.uniqueViolation"
+	// DetailForeignKeyReferenced is the detail of deleting a referenced row
+	DetailForeignKeyReferenced = "db.foreignKeyReferenced"
+	// DetailForeignKeyMissing is the detail of referencing a missing row
+	DetailForeignKeyMissing = "db.foreignKeyMissing"
+	// DetailNotNullViolation has the name parameter
+	DetailNotNullViolation = "db.notNull"
+	// DetailNotNullUnnamed is the detail of a not null violation of a
+	// column the driver doesn't name
+	DetailNotNullUnnamed = "db.notNullUnnamed"
+)
+
+// Messages holds the message catalogs of the standard detail text, one
+// file per language.
+//
+//go:embed messages/*.json
+var Messages embed.FS
+
+func init() {
+	if err := problems.DefaultTranslations.LoadCatalogFS(Messages, "messages/*.json"); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,342 @@
+package standard
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"tjdavis.dev/problems"
+)
+
+// OAuth 2.0 error codes of RFC 6749 section 5.2 and RFC 6750 section 3.1
+const (
+	OAuthInvalidRequest         = "invalid_request"
+	OAuthInvalidToken           = "invalid_token"
+	OAuthInsufficientScope      = "insufficient_scope"
+	OAuthInvalidClient          = "invalid_client"
+	OAuthInvalidGrant           = "invalid_grant"
+	OAuthUnauthorizedClient     = "unauthorized_client"
+	OAuthUnsupportedGrantType   = "unsupported_grant_type"
+	OAuthInvalidScope           = "invalid_scope"
+	OAuthAccessDenied           = "access_denied"
+	OAuthServerError            = "server_error"
+	OAuthTemporarilyUnavailable = "temporarily_unavailable"
+)
+
+// OAuthError is an OAuth 2.0 error response:
+//
+//	{"error": "invalid_token", "error_description": "The access token expired"}
+type OAuthError struct {
+	// Code is the error code, eg. invalid_token.  It is empty for a request
+	// without credentials (RFC 6750 section 3.1).
+	Code             string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+	ErrorURI         string `json:"error_uri,omitempty"`
+	// Scope lists the scopes required, separated by spaces
+	Scope string `json:"scope,omitempty"`
+}
+
+// Challenge converts the error to its WWW-Authenticate form
+func (e OAuthError) Challenge(realm string) Challenge {
+	return Challenge{
+		Realm:            realm,
+		Error:            e.Code,
+		ErrorDescription: e.ErrorDescription,
+		ErrorURI:         e.ErrorURI,
+		Scope:            strings.Fields(e.Scope),
+	}
+}
+
+// OAuthError converts the challenge to the error response form
+func (c Challenge) OAuthError() OAuthError {
+	return OAuthError{
+		Code:             c.Error,
+		ErrorDescription: c.ErrorDescription,
+		ErrorURI:         c.ErrorURI,
+		Scope:            strings.Join(c.Scope, " "),
+	}
+}
+
+// oauthStatus is the status of each error code; RFC 6749 uses 400 unless
+// the client failed to authenticate
+var oauthStatus = map[string]int{
+	"":                          http.StatusUnauthorized,
+	OAuthInvalidRequest:         http.StatusBadRequest,
+	OAuthInvalidToken:           http.StatusUnauthorized,
+	OAuthInsufficientScope:      http.StatusForbidden,
+	OAuthInvalidClient:          http.StatusUnauthorized,
+	OAuthAccessDenied:           http.StatusForbidden,
+	OAuthServerError:            http.StatusInternalServerError,
+	OAuthTemporarilyUnavailable: http.StatusServiceUnavailable,
+}
+
+// FromOAuthError converts an OAuth 2.0 error to the standard problem:
+//
+//   - no code is GetNoAccessResponse
+//   - invalid_token is GetInvalidTokenResponse, or GetExpiredTokenResponse
+//     when the description says the token expired
+//   - insufficient_scope is GetMissingScopeResponse with the scope as the
+//     requiredScopes
+//   - access_denied is GetMissingPermission
+//   - invalid_request is a TypeBadRequest problem
+//   - server_error is GetInternalErrorResponse
+//   - temporarily_unavailable is GetServiceUnavailableResponse, with a
+//     retryAfter of one second as OAuth doesn't say when to retry
+//   - the other codes, eg. invalid_client or invalid_grant, are about:blank
+//     problems; they aren't about the access token
+//
+// The description replaces the detail of the problem.  The status sets the
+// status of the about:blank problems, 0 using the status of the code; the
+// other problems keep the status of their type.
+func FromOAuthError(e OAuthError, status int) *problems.Problem {
+	var prob *problems.Problem
+	switch e.Code {
+	case "":
+		prob = GetNoAccessResponse()
+	case OAuthInvalidToken:
+		if strings.Contains(strings.ToLower(e.ErrorDescription), "expired") {
+			prob = GetExpiredTokenResponse()
+		} else {
+			prob = GetInvalidTokenResponse()
+		}
+	case OAuthInsufficientScope:
+		prob = GetMissingScopeResponse(strings.Fields(e.Scope))
+	case OAuthAccessDenied:
+		prob = GetMissingPermission()
+	case OAuthInvalidRequest:
+		prob = problems.New(http.StatusBadRequest, fmt.Sprintf("OAuth error %s", e.Code))
+		_ = prob.Set("Type", TypeBadRequest)
+		_ = prob.Set("Title", http.StatusText(http.StatusBadRequest))
+	case OAuthServerError:
+		prob = GetInternalErrorResponse(e.ErrorDescription)
+	case OAuthTemporarilyUnavailable:
+		prob = GetServiceUnavailableResponse(0)
+	default:
+		if status == 0 {
+			status = http.StatusBadRequest
+			if code, ok := oauthStatus[e.Code]; ok {
+				status = code
+			}
+		}
+		prob = problems.New(status, fmt.Sprintf("OAuth error %s", e.Code))
+		prob.Title = http.StatusText(status)
+	}
+	if e.ErrorDescription != "" {
+		prob.SetDetailKey("")
+		prob.Detail = e.ErrorDescription
+	}
+	switch e.Code {
+	case "", OAuthInvalidToken, OAuthInsufficientScope:
+		// keep the description and URI of the original challenge
+		prob.Header().Set("WWW-Authenticate", e.Challenge(Realm).String())
+	}
+	return prob
+}
+
+// ToOAuthError converts a problem to an OAuth 2.0 error.  The standard
+// authentication, authorization, bad request, internal and unavailable
+// problems have their own codes; other problems with a status of 500 or
+// more are server_error and the rest invalid_request.
+func ToOAuthError(prob *problems.Problem) OAuthError {
+	e := OAuthError{ErrorDescription: prob.Detail}
+	switch prob.Type {
+	case TypeNoAccessToken:
+		return OAuthError{}
+	case TypeInvalidToken, TypeTokenExpired:
+		e.Code = OAuthInvalidToken
+	case TypeMissingScope:
+		e.Code = OAuthInsufficientScope
+		e.Scope = strings.Join(RequiredScopes(prob), " ")
+	case TypeMissingPermission:
+		e.Code = OAuthAccessDenied
+	case TypeServiceUnavailable:
+		e.Code = OAuthTemporarilyUnavailable
+	default:
+		if prob.Status >= http.StatusInternalServerError {
+			e.Code = OAuthServerError
+		} else {
+			e.Code = OAuthInvalidRequest
+		}
+	}
+	return e
+}
+
+// RequiredScopes returns the requiredScopes of a missing scope problem,
+// whether it was created by GetMissingScopeResponse or decoded
+func RequiredScopes(prob *problems.Problem) []string {
+	for _, key := range []string{"requiredScopes", "requiredscopes"} {
+		switch scopes := prob.Get(key).(type) {
+		case []string:
+			return scopes
+		case []interface{}:
+			list := make([]string, 0, len(scopes))
+			for _, scope := range scopes {
+				list = append(list, fmt.Sprint(scope))
+			}
+			return list
+		}
+	}
+	return nil
+}
+
+// WriteOAuthError writes the problem as an OAuth 2.0 error response with
+// the status of the problem.  401 and 403 responses have the
+// WWW-Authenticate header of the problem, or one built from the error.
+func WriteOAuthError(w http.ResponseWriter, prob *problems.Problem) error {
+	e := ToOAuthError(prob)
+	for key, values := range prob.Header() {
+		w.Header()[key] = append([]string(nil), values...)
+	}
+	status := prob.Status
+	if status == 0 {
+		status = oauthStatus[e.Code]
+	}
+	if (status == http.StatusUnauthorized || status == http.StatusForbidden) && w.Header().Get("WWW-Authenticate") == "" {
+		w.Header().Set("WWW-Authenticate", e.Challenge(Realm).String())
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	if e.Code == "" {
+		w.WriteHeader(status)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(e)
+}
+
+// ParseChallenge parses a WWW-Authenticate header that starts with a
+// Bearer challenge.  Challenges of other schemes after it are ignored.
+func ParseChallenge(header string) (Challenge, error) {
+	var c Challenge
+	header = strings.TrimSpace(header)
+	end := strings.IndexAny(header, " ,")
+	if end < 0 {
+		end = len(header)
+	}
+	if !strings.EqualFold(header[:end], "Bearer") {
+		return c, problems.Errorf(500, "%q is not a Bearer challenge", header)
+	}
+	for params := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(header[end:]), ",")); params != ""; {
+		name, rest, ok := strings.Cut(params, "=")
+		if !ok {
+			return c, problems.Errorf(500, "Invalid challenge parameter %q", params)
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if strings.ContainsAny(name, " \t") {
+			// the scheme and first parameter of the next challenge
+			break
+		}
+		value, rest, err := authParamValue(strings.TrimSpace(rest))
+		if err != nil {
+			return c, err
+		}
+		switch name {
+		case "realm":
+			c.Realm = value
+		case "scope":
+			c.Scope = strings.Fields(value)
+		case "error":
+			c.Error = value
+		case "error_description":
+			c.ErrorDescription = value
+		case "error_uri":
+			c.ErrorURI = value
+		}
+		params = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(rest), ","))
+	}
+	return c, nil
+}
+
+// BearerChallenge finds and parses the Bearer challenge of the
+// WWW-Authenticate header values, which may hold challenges of several
+// schemes, eg. Basic realm="api", Bearer error="invalid_token"
+func BearerChallenge(values []string) (Challenge, bool) {
+	for _, header := range values {
+		quoted, escaped, start := false, false, true
+		for i := 0; i < len(header); i++ {
+			ch := header[i]
+			switch {
+			case escaped:
+				escaped = false
+			case quoted:
+				escaped = ch == '\\'
+				quoted = ch != '"'
+			case ch == '"':
+				quoted = true
+			case ch == ',':
+				start = true
+			case ch == ' ' || ch == '\t':
+			default:
+				if start && len(header)-i >= 6 && strings.EqualFold(header[i:i+6], "Bearer") &&
+					(len(header) == i+6 || strings.IndexByte(" ,", header[i+6]) >= 0) {
+					if c, err := ParseChallenge(header[i:]); err == nil {
+						return c, true
+					}
+				}
+				start = false
+			}
+		}
+	}
+	return Challenge{}, false
+}
+
+// authParamValue reads a token or quoted-string from the start of s
+func authParamValue(s string) (string, string, error) {
+	if !strings.HasPrefix(s, `"`) {
+		end := strings.IndexByte(s, ',')
+		if end < 0 {
+			end = len(s)
+		}
+		return strings.TrimSpace(s[:end]), s[end:], nil
+	}
+	var value strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+			if i < len(s) {
+				value.WriteByte(s[i])
+			}
+		case '"':
+			return value.String(), s[i+1:], nil
+		default:
+			value.WriteByte(s[i])
+		}
+	}
+	return "", "", problems.Errorf(500, "Unterminated quoted string in %q", s)
+}
+
+// FromOAuthResponse converts an OAuth 2.0 error response, eg. from a
+// gateway or authorization server, to a problem.  The error is read from
+// a JSON body or, when that has no error code, from the Bearer challenge
+// of the WWW-Authenticate header.  Challenges of other schemes are
+// ignored.  A 401 with a Bearer challenge without an error code is
+// GetNoAccessResponse; any other response without an error code isn't an
+// OAuth error, so it is the problem of its status with the standard type
+// of the status.
+func FromOAuthResponse(resp *http.Response) (*problems.Problem, error) {
+	var e OAuthError
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "application/json" && resp.Body != nil {
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, problems.FromError(err)
+		}
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &e); err != nil {
+				return nil, problems.Errorf(500, "Invalid OAuth error response: %v", err)
+			}
+		}
+	}
+	if e.Code == "" {
+		c, ok := BearerChallenge(resp.Header.Values("WWW-Authenticate"))
+		if !ok || (c.Error == "" && resp.StatusCode != http.StatusUnauthorized) {
+			return statusProblem(resp.StatusCode), nil
+		}
+		e = c.OAuthError()
+	}
+	return FromOAuthError(e, resp.StatusCode), nil
+}
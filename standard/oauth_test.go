@@ -0,0 +1,209 @@
+package standard
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"tjdavis.dev/problems"
+)
+
+func TestFromOAuthError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    OAuthError
+		status int
+		want   string
+		code   int
+	}{
+		{"Test no credentials", OAuthError{}, 0, TypeNoAccessToken, 401},
+		{"Test invalid token", OAuthError{Code: OAuthInvalidToken, ErrorDescription: "Bad signature"}, 0, TypeInvalidToken, 401},
+		{"Test expired token", OAuthError{Code: OAuthInvalidToken, ErrorDescription: "The access token expired"}, 0, TypeTokenExpired, 401},
+		{"Test insufficient scope", OAuthError{Code: OAuthInsufficientScope, Scope: "users:read users:write"}, 0, TypeMissingScope, 403},
+		{"Test access denied", OAuthError{Code: OAuthAccessDenied}, 0, TypeMissingPermission, 403},
+		{"Test invalid request", OAuthError{Code: OAuthInvalidRequest}, 0, TypeBadRequest, 400},
+		{"Test invalid client", OAuthError{Code: OAuthInvalidClient}, 0, "", 401},
+		{"Test server error", OAuthError{Code: OAuthServerError}, 0, TypeInternalServerError, 500},
+		{"Test unavailable", OAuthError{Code: OAuthTemporarilyUnavailable}, 0, TypeServiceUnavailable, 503},
+		{"Test invalid grant", OAuthError{Code: OAuthInvalidGrant}, 0, "", 400},
+		{"Test status", OAuthError{Code: OAuthInvalidGrant}, 401, "", 401},
+		{"Test status of a type", OAuthError{Code: OAuthInsufficientScope}, 401, TypeMissingScope, 403},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prob := FromOAuthError(tt.err, tt.status)
+			if prob.Type != tt.want || prob.Status != tt.code {
+				t.Errorf("FromOAuthError() = %s %d, want %s %d", prob.Type, prob.Status, tt.want, tt.code)
+			}
+			if tt.err.ErrorDescription != "" && prob.Detail != tt.err.ErrorDescription {
+				t.Errorf("Detail = %v, want %v", prob.Detail, tt.err.ErrorDescription)
+			}
+		})
+	}
+}
+
+func TestOAuthError_RoundTrip(t *testing.T) {
+	defer func(realm string) { Realm = realm }(Realm)
+	Realm = "api"
+	prob := FromOAuthError(OAuthError{Code: OAuthInsufficientScope, Scope: "users:read users:write"}, 0)
+	if got, want := RequiredScopes(prob), []string{"users:read", "users:write"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RequiredScopes() = %v, want %v", got, want)
+	}
+	if got, want := prob.Header().Get("WWW-Authenticate"),
+		`Bearer realm="api", scope="users:read users:write", error="insufficient_scope"`; got != want {
+		t.Errorf("WWW-Authenticate = %v, want %v", got, want)
+	}
+
+	// a decoded problem has the lowercase wire names
+	data, _ := json.Marshal(prob)
+	decoded := &problems.Problem{}
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+	e := ToOAuthError(decoded)
+	if e.Code != OAuthInsufficientScope || e.Scope != "users:read users:write" {
+		t.Errorf("ToOAuthError() = %+v", e)
+	}
+
+	if e := ToOAuthError(GetExpiredTokenResponse()); e.Code != OAuthInvalidToken {
+		t.Errorf("ToOAuthError() = %+v, want invalid_token", e)
+	}
+	if e := ToOAuthError(GetNoAccessResponse()); e.Code != "" {
+		t.Errorf("ToOAuthError() = %+v, want no code", e)
+	}
+}
+
+func TestWriteOAuthError(t *testing.T) {
+	w := httptest.NewRecorder()
+	if err := WriteOAuthError(w, GetExpiredTokenResponse()); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401", w.Code)
+	}
+	if got := w.Header().Get("WWW-Authenticate"); !strings.Contains(got, `error="invalid_token"`) {
+		t.Errorf("WWW-Authenticate = %v", got)
+	}
+	var e OAuthError
+	if err := json.NewDecoder(w.Body).Decode(&e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Code != OAuthInvalidToken {
+		t.Errorf("error = %v, want invalid_token", e.Code)
+	}
+
+	w = httptest.NewRecorder()
+	_ = WriteOAuthError(w, GetNoAccessResponse())
+	if w.Body.Len() != 0 || w.Header().Get("WWW-Authenticate") == "" {
+		t.Errorf("no access response = %q %v", w.Body.String(), w.Header())
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	tests := []struct {
+		header  string
+		want    Challenge
+		wantErr bool
+	}{
+		{"Bearer", Challenge{}, false},
+		{`Bearer realm="api", error="invalid_token", error_description="The \"token\" expired"`,
+			Challenge{Realm: "api", Error: "invalid_token", ErrorDescription: `The "token" expired`}, false},
+		{`bearer scope="a b", error=insufficient_scope`,
+			Challenge{Scope: []string{"a", "b"}, Error: "insufficient_scope"}, false},
+		{`Basic realm="api"`, Challenge{}, true},
+		{`Bearer realm="api`, Challenge{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseChallenge(tt.header)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseChallenge(%q) error = %v, wantErr %v", tt.header, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseChallenge(%q) = %+v, want %+v", tt.header, got, tt.want)
+		}
+		if !tt.wantErr {
+			if again, _ := ParseChallenge(got.String()); !reflect.DeepEqual(again, got) {
+				t.Errorf("ParseChallenge(String()) = %+v, want %+v", again, got)
+			}
+		}
+	}
+}
+
+func TestFromOAuthResponse(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusUnauthorized,
+		Header: http.Header{
+			"Content-Type":     {"application/json;charset=UTF-8"},
+			"Www-Authenticate": {`Bearer error="invalid_token"`},
+		},
+		Body: io.NopCloser(strings.NewReader(`{"error":"invalid_token","error_description":"The access token expired"}`)),
+	}
+	prob, err := FromOAuthResponse(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prob.Type != TypeTokenExpired || prob.Detail != "The access token expired" {
+		t.Errorf("FromOAuthResponse() = %s %q", prob.Type, prob.Detail)
+	}
+
+	resp = &http.Response{
+		StatusCode: http.StatusForbidden,
+		Header:     http.Header{"Www-Authenticate": {`Bearer error="insufficient_scope", scope="users:write"`}},
+	}
+	prob, err = FromOAuthResponse(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := RequiredScopes(prob); !reflect.DeepEqual(got, []string{"users:write"}) {
+		t.Errorf("RequiredScopes() = %v", got)
+	}
+}
+
+func TestFromOAuthResponse_Challenges(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		header []string
+		body   string
+		typ    string
+	}{
+		{"Test other scheme with body", 401, []string{`Basic realm="x"`}, `{"error":"invalid_token"}`, TypeInvalidToken},
+		{"Test body wins", 401, []string{`Bearer error="insufficient_scope", scope="a"`}, `{"error":"invalid_token"}`, TypeInvalidToken},
+		{"Test multiple challenges", 403, []string{`Basic realm="a, Bearer x", Bearer realm="api", error="insufficient_scope", scope="users:read"`},
+			"", TypeMissingScope},
+		{"Test multiple headers", 401, []string{`Basic realm="x"`, `Bearer error="invalid_token"`}, "", TypeInvalidToken},
+		{"Test no Bearer challenge", 401, []string{`Basic realm="x"`}, "", ""},
+		{"Test Bearer challenge without error", 401, []string{`Bearer realm="api"`}, "", TypeNoAccessToken},
+		{"Test Bearer challenge without error on 403", 403, []string{`Bearer realm="api"`}, "", ""},
+		{"Test non-OAuth 4xx", 400, nil, `{"message":"bad"}`, TypeBadRequest},
+		{"Test non-OAuth 5xx", 500, nil, "", TypeInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{
+				StatusCode: tt.status,
+				Header:     http.Header{"Www-Authenticate": tt.header},
+				Body:       io.NopCloser(strings.NewReader(tt.body)),
+			}
+			if tt.body != "" {
+				resp.Header.Set("Content-Type", "application/json")
+			}
+			prob, err := FromOAuthResponse(resp)
+			if err != nil {
+				t.Fatalf("FromOAuthResponse() error = %v", err)
+			}
+			if prob.Type != tt.typ || prob.Status != tt.status {
+				t.Errorf("FromOAuthResponse() = %d %v, want %d %v", prob.Status, prob.Type, tt.status, tt.typ)
+			}
+		})
+	}
+	c, ok := BearerChallenge([]string{`Basic realm="a, Bearer x", Bearer realm="api", error="insufficient_scope", scope="users:read", Digest realm="d"`})
+	if !ok || c.Realm != "api" || !reflect.DeepEqual(c.Scope, []string{"users:read"}) {
+		t.Errorf("BearerChallenge() = %+v, %v", c, ok)
+	}
+}
@@ -0,0 +1,94 @@
+package standard
+
+import (
+	"reflect"
+	"strings"
+)
+
+// embeddedPrefix marks the namespace segment of an embedded struct so it
+// can be dropped from the JSON name; encoding/json promotes the fields of
+// an untagged embedded struct.  Go identifiers can't contain it.
+const embeddedPrefix = "+"
+
+// JSONFieldName is a validator TagNameFunc that names fields by their json
+// tag so validation issues use the names the client sent.  FieldName falls
+// back to it for fields that aren't request parameters.
+func JSONFieldName(fld reflect.StructField) string {
+	name := strings.Split(fld.Tag.Get("json"), ",")[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" && fld.Anonymous {
+		return embeddedPrefix + fld.Name
+	}
+	return name
+}
+
+// pathPart is a field name or, when index is set, a slice index or map key
+type pathPart struct {
+	name  string
+	index bool
+}
+
+// namespacePath splits a validator namespace such as
+// `CreateUser.items[3].sku` into its path below the root struct:
+// items, 3, sku.  Embedded structs are removed.
+func namespacePath(namespace string) []pathPart {
+	var path []pathPart
+	segments := strings.Split(namespace, ".")
+	if len(segments) > 1 {
+		// the first segment is the name of the validated struct
+		segments = segments[1:]
+	}
+	for _, segment := range segments {
+		for segment != "" {
+			open := strings.IndexByte(segment, '[')
+			end := strings.IndexByte(segment, ']')
+			if open < 0 || end < open {
+				if !strings.HasPrefix(segment, embeddedPrefix) {
+					path = append(path, pathPart{name: segment})
+				}
+				break
+			}
+			if open > 0 && !strings.HasPrefix(segment, embeddedPrefix) {
+				path = append(path, pathPart{name: segment[:open]})
+			}
+			path = append(path, pathPart{name: segment[open+1 : end], index: true})
+			segment = segment[end+1:]
+		}
+	}
+	return path
+}
+
+// JSONName converts a validator namespace to the dotted name of the field
+// as the client sent it, eg. `CreateUser.Items[3].SKU` becomes
+// `items[3].sku` when the fields are tagged with those json names.
+func JSONName(namespace string) string {
+	var name strings.Builder
+	for _, part := range namespacePath(namespace) {
+		switch {
+		case part.index:
+			name.WriteString("[" + part.name + "]")
+		case name.Len() > 0:
+			name.WriteString("." + part.name)
+		default:
+			name.WriteString(part.name)
+		}
+	}
+	return name.String()
+}
+
+// JSONPointer converts a validator namespace to an RFC 6901 JSON Pointer,
+// eg. `CreateUser.items[3].sku` becomes `/items/3/sku`.
+func JSONPointer(namespace string) string {
+	var pointer strings.Builder
+	for _, part := range namespacePath(namespace) {
+		pointer.WriteString("/")
+		pointer.WriteString(escapePointer(part.name))
+	}
+	return pointer.String()
+}
+
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,80 @@
+package standard
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type testAudit struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+type testItem struct {
+	SKU      string `json:"sku" validate:"required"`
+	Quantity int    `json:"quantity" validate:"min=1"`
+}
+
+type testOrder struct {
+	testAudit
+	Items    []testItem          `json:"items" validate:"dive"`
+	Labels   map[string]testItem `json:"labels" validate:"dive"`
+	Customer struct {
+		Email string `json:"e/mail" validate:"email"`
+	} `json:"customer"`
+}
+
+func TestJSONName(t *testing.T) {
+	tests := []struct {
+		namespace string
+		name      string
+		pointer   string
+	}{
+		{"testOrder.items[3].sku", "items[3].sku", "/items/3/sku"},
+		{"testOrder.labels[gift].quantity", "labels[gift].quantity", "/labels/gift/quantity"},
+		{"testOrder.+testAudit.reason", "reason", "/reason"},
+		{"testOrder.customer.e/mail", "customer.e/mail", "/customer/e~1mail"},
+		{"testOrder.matrix[1][2]", "matrix[1][2]", "/matrix/1/2"},
+		{"name", "name", "/name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.namespace, func(t *testing.T) {
+			if got := JSONName(tt.namespace); got != tt.name {
+				t.Errorf("JSONName() = %v, want %v", got, tt.name)
+			}
+			if got := JSONPointer(tt.namespace); got != tt.pointer {
+				t.Errorf("JSONPointer() = %v, want %v", got, tt.pointer)
+			}
+		})
+	}
+}
+
+func TestValidation_ResponsePointers(t *testing.T) {
+	v := testValidation(t)
+	order := testOrder{
+		Items:  []testItem{{SKU: "a", Quantity: 1}, {Quantity: 1}},
+		Labels: map[string]testItem{"gift": {SKU: "b"}},
+	}
+	order.Customer.Email = "nobody"
+	err := v.Validate.Struct(order)
+	prob := v.Response("en", err.(validator.ValidationErrors))
+
+	want := map[string]string{
+		"reason":                "/reason",
+		"items[1].sku":          "/items/1/sku",
+		"labels[gift].quantity": "/labels/gift/quantity",
+		"customer.e/mail":       "/customer/e~1mail",
+	}
+	issues := prob.Issues()
+	if len(issues) != len(want) {
+		t.Fatalf("Response() issues = %d, want %d", len(issues), len(want))
+	}
+	for _, issue := range issues {
+		name := issue.Name
+		if pointer, ok := want[name]; !ok {
+			t.Errorf("Response() unexpected issue name %v", name)
+		} else if issue.Pointer != pointer {
+			t.Errorf("Response() pointer of %v = %v, want %v", name, issue.Pointer, pointer)
+		}
+	}
+}
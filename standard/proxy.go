@@ -0,0 +1,172 @@
+package standard
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+
+	"tjdavis.dev/problems"
+)
+
+// maxProxiedProblem is the largest problem of an upstream that
+// ProxyProblems edits; larger bodies are passed through unchanged
+const maxProxiedProblem = 1 << 20
+
+// ProxyProblems makes a httputil.ReverseProxy answer with problems, so the
+// errors of the gateway and of the services behind it share one format:
+//
+//	pp := &standard.ProxyProblems{Upstream: "orders", Hop: "gateway"}
+//	proxy.ErrorHandler = pp.ErrorHandler
+//	proxy.ModifyResponse = pp.ModifyResponse
+type ProxyProblems struct {
+	// Upstream names the upstream in the upstream extension of the
+	// problems; empty uses the host of the upstream request
+	Upstream string
+	// Sanitize, if set, is applied to the members of the problems of the
+	// upstream before they are passed through, eg. to remove internal
+	// details.  The members are raw JSON keyed by their name in the body.
+	Sanitize func(members map[string]json.RawMessage)
+	// Hop, if set, is appended to the hops extension of the problems of
+	// the upstream passed through, recording the gateways they crossed
+	Hop string
+}
+
+// upstream returns the name of the upstream of the request
+func (pp *ProxyProblems) upstream(r *http.Request) string {
+	if pp.Upstream != "" || r == nil {
+		return pp.Upstream
+	}
+	return r.URL.Host
+}
+
+// ErrorHandler is the httputil.ReverseProxy ErrorHandler.  It renders a
+// 504 problem when the upstream timed out and a 502 problem for any other
+// error, both with the upstream extension.  Nothing is rendered for a
+// client that disconnected.
+func (pp *ProxyProblems) ErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	if IsClientDisconnect(r, err) {
+		w.WriteHeader(problems.StatusClientClosedRequest)
+		return
+	}
+	var netErr net.Error
+	prob := GetBadGatewayResponse()
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		prob = GetGatewayTimeoutResponse()
+	}
+	if upstream := pp.upstream(r); upstream != "" {
+		_ = prob.Set("upstream", upstream)
+	}
+	_ = prob.Render(w, r)
+}
+
+// ModifyResponse is the httputil.ReverseProxy ModifyResponse hook.  The
+// 4xx and 5xx responses of the upstream that aren't problems are replaced
+// by a problem of the same status, converted like InterceptErrors does,
+// with the upstream extension unless it is about:blank.  Problems of the
+// upstream are passed through with their members unchanged, except for
+// Sanitize and the Hop when they are set; problems over 1 MiB are passed
+// through as they are.  Successful responses and encoded bodies, eg.
+// gzip, are left alone.  The problems are localized like Render does.
+func (pp *ProxyProblems) ModifyResponse(resp *http.Response) error {
+	if resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+	if encoding := resp.Header.Get("Content-Encoding"); encoding != "" && encoding != "identity" {
+		return nil
+	}
+	if isProblemMediaType(resp.Header.Get("Content-Type")) {
+		if pp.Sanitize == nil && pp.Hop == "" {
+			return nil
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, maxProxiedProblem+1))
+		if err != nil {
+			_ = resp.Body.Close()
+			return err
+		}
+		if len(data) > maxProxiedProblem {
+			// too large to edit; pass the body on unchanged
+			resp.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(data), resp.Body), resp.Body}
+			return nil
+		}
+		_ = resp.Body.Close()
+		// the members are edited as raw JSON so they keep their names
+		var members map[string]json.RawMessage
+		if err := json.Unmarshal(data, &members); err != nil {
+			// not a problem after all; pass the body on unchanged
+			setBody(resp, data)
+			return nil
+		}
+		if pp.Sanitize != nil {
+			pp.Sanitize(members)
+		}
+		if pp.Hop != "" {
+			var hops []json.RawMessage
+			_ = json.Unmarshal(members["hops"], &hops)
+			hop, _ := json.Marshal(pp.Hop)
+			members["hops"], _ = json.Marshal(append(hops, hop))
+		}
+		data, err = json.Marshal(members)
+		if err != nil {
+			return err
+		}
+		setBody(resp, append(data, '\n'))
+		return nil
+	}
+
+	var body bytes.Buffer
+	_, err := io.Copy(&body, io.LimitReader(resp.Body, maxInterceptedBody))
+	_ = resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	prob := interceptedProblem(resp.Request, resp.StatusCode, resp.Header, body.String())
+	if upstream := pp.upstream(resp.Request); upstream != "" && hasType(prob) {
+		_ = prob.Set("upstream", upstream)
+	}
+	// rendered by Render so it negotiates the language like local problems
+	rendered := &responseBuffer{header: make(http.Header)}
+	if err := prob.Render(rendered, resp.Request); err != nil {
+		return err
+	}
+	for key, values := range rendered.header {
+		if key == "Vary" {
+			resp.Header[key] = append(resp.Header[key], values...)
+		} else {
+			resp.Header[key] = values
+		}
+	}
+	setBody(resp, rendered.body.Bytes())
+	return nil
+}
+
+// hasType reports whether the problem has a type, so it can have
+// extensions
+func hasType(prob *problems.Problem) bool {
+	return prob.Type != "" && prob.Type != "about:blank"
+}
+
+// responseBuffer is the http.ResponseWriter a problem is rendered into
+// before it replaces the body of an upstream response
+type responseBuffer struct {
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (b *responseBuffer) Header() http.Header         { return b.header }
+func (b *responseBuffer) Write(p []byte) (int, error) { return b.body.Write(p) }
+func (b *responseBuffer) WriteHeader(int)             {}
+
+// setBody replaces the body of the response
+func setBody(resp *http.Response, data []byte) {
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	resp.ContentLength = int64(len(data))
+	resp.Header.Set("Content-Length", strconv.Itoa(len(data)))
+}
@@ -0,0 +1,161 @@
+package standard
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"tjdavis.dev/problems"
+)
+
+func TestProxyProblems(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			_, _ = io.WriteString(w, "ok")
+		case "/plain":
+			http.Error(w, "order 7 not found", http.StatusNotFound)
+		case "/problem":
+			w.Header().Set("Content-Type", problems.ProblemMediaType)
+			w.WriteHeader(http.StatusConflict)
+			_, _ = io.WriteString(w, `{"type":"`+TypeConflict+`","status":409,"detail":"Order 7 was already shipped",`+
+				`"orderId":"42","invalidParams":[{"name":"qty"}],"internal":"db-3","hops":["edge"]}`)
+		case "/large":
+			w.Header().Set("Content-Type", problems.ProblemMediaType)
+			w.WriteHeader(http.StatusConflict)
+			_, _ = io.WriteString(w, `{"type":"`+TypeConflict+`","detail":"`+strings.Repeat("x", maxProxiedProblem)+`"}`)
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		}
+	}))
+	defer backend.Close()
+	target, _ := url.Parse(backend.URL)
+
+	pp := &ProxyProblems{
+		Upstream: "orders",
+		Hop:      "gateway",
+		Sanitize: func(members map[string]json.RawMessage) { delete(members, "internal") },
+	}
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	proxy.ErrorHandler = pp.ErrorHandler
+	proxy.ModifyResponse = pp.ModifyResponse
+	proxy.Transport = &http.Transport{ResponseHeaderTimeout: 50 * time.Millisecond}
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		want   map[string]interface{}
+	}{
+		{"Test success", "/ok", http.StatusOK, nil},
+		{"Test plain text error", "/plain", http.StatusNotFound,
+			map[string]interface{}{"type": TypeNotFound, "detail": "order 7 not found", "upstream": "orders"}},
+		{"Test upstream problem", "/problem", http.StatusConflict,
+			map[string]interface{}{"type": TypeConflict, "detail": "Order 7 was already shipped", "orderId": "42",
+				"invalidParams": []interface{}{map[string]interface{}{"name": "qty"}}, "hops": []interface{}{"edge", "gateway"}}},
+		{"Test large upstream problem", "/large", http.StatusConflict,
+			map[string]interface{}{"type": TypeConflict, "detail": strings.Repeat("x", maxProxiedProblem), "hops": nil}},
+		{"Test timeout", "/slow", http.StatusGatewayTimeout,
+			map[string]interface{}{"type": TypeGatewayTimeout, "upstream": "orders"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			proxy.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if tt.want == nil {
+				if w.Body.String() != "ok" {
+					t.Errorf("body = %q, want ok", w.Body.String())
+				}
+				return
+			}
+			if ct := w.Header().Get("Content-Type"); ct != problems.ProblemMediaType {
+				t.Errorf("Content-Type = %v", ct)
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("body %q: %v", w.Body.String(), err)
+			}
+			for key, want := range tt.want {
+				if got, _ := json.Marshal(body[key]); string(got) != mustJSON(want) {
+					t.Errorf("%s = %s, want %s", key, got, mustJSON(want))
+				}
+			}
+			if _, ok := body["internal"]; ok {
+				t.Errorf("internal wasn't sanitized: %v", body)
+			}
+		})
+	}
+}
+
+func TestProxyProblems_Unreachable(t *testing.T) {
+	backend := httptest.NewServer(http.NotFoundHandler())
+	target, _ := url.Parse(backend.URL)
+	backend.Close()
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	proxy.ErrorHandler = (&ProxyProblems{}).ErrorHandler
+	w := httptest.NewRecorder()
+	proxy.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != http.StatusBadGateway {
+		t.Fatalf("status = %d, want 502", w.Code)
+	}
+	var body map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	if body["type"] != TypeBadGateway || body["upstream"] != target.Host {
+		t.Errorf("body = %v", body)
+	}
+}
+
+func TestProxyProblems_Language(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusNotFound,
+		Header:     http.Header{"Content-Type": {"text/plain"}, "Vary": {"Origin"}},
+		Body:       io.NopCloser(strings.NewReader("order 7 not found")),
+		Request:    httptest.NewRequest("GET", "/orders/7", nil),
+	}
+	resp.Request.Header.Set("Accept-Language", "de, fr;q=0.5")
+	if err := (&ProxyProblems{}).ModifyResponse(resp); err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/orders/7", nil)
+	r.Header.Set("Accept-Language", "de, fr;q=0.5")
+	_ = GetMissingResource(MissingResourceParam{ResourceType: "Order", ResourceValue: 7}).Render(w, r)
+	if got, want := resp.Header.Get("Content-Language"), w.Header().Get("Content-Language"); got != want || got != "fr" {
+		t.Errorf("Content-Language = %q, want %q like Render", got, want)
+	}
+	if got := resp.Header.Values("Vary"); len(got) != 2 || got[1] != "Accept-Language" {
+		t.Errorf("Vary = %v, want Origin and Accept-Language", got)
+	}
+}
+
+func mustJSON(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+func TestProxyProblems_NoRequest(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusMethodNotAllowed,
+		Header:     http.Header{"Allow": {"GET"}, "Content-Type": {"text/plain"}},
+		Body:       io.NopCloser(strings.NewReader("method not allowed")),
+	}
+	if err := (&ProxyProblems{Upstream: "orders"}).ModifyResponse(resp); err != nil {
+		t.Fatal(err)
+	}
+	var body map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body["type"] != TypeMethodNotAllowed || body["upstream"] != "orders" {
+		t.Errorf("body = %v", body)
+	}
+}
@@ -0,0 +1,55 @@
+package standard
+
+// MissingScopeProblem is the typed form of a TypeMissingScope problem,
+// suitable for decoding a rendered problem.
+type MissingScopeProblem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title,omitempty"`
+	Status   int    `json:"status,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// RequiredScopes lists the scopes required to invoke the operation
+	RequiredScopes []string `json:"requiredscopes"`
+}
+
+// InputValidationIssue is a single issue of an InputValidationProblem
+type InputValidationIssue struct {
+	Type   string `json:"type"`
+	Title  string `json:"title,omitempty"`
+	Detail string `json:"detail,omitempty"`
+	// In is where the input was found: body, header, path or query
+	In string `json:"in,omitempty"`
+	// Name is the name of the input in error
+	Name string `json:"name,omitempty"`
+	// Pointer is the RFC 6901 JSON Pointer to the input in a body
+	Pointer string `json:"pointer,omitempty"`
+	// Value is the value that was provided
+	Value interface{} `json:"value,omitempty"`
+}
+
+// InputValidationProblem is the typed form of the TypeBadRequest and
+// TypeNotFound problems that list their issues.
+type InputValidationProblem struct {
+	Type     string                 `json:"type"`
+	Title    string                 `json:"title,omitempty"`
+	Status   int                    `json:"status,omitempty"`
+	Detail   string                 `json:"detail,omitempty"`
+	Instance string                 `json:"instance,omitempty"`
+	Issues   []InputValidationIssue `json:"issues"`
+	// OmittedIssues counts the issues over problems.MaxIssues
+	OmittedIssues int `json:"omittedissues,omitempty"`
+}
+
+// Schemas are the typed problem structs keyed by their OpenAPI schema name
+var Schemas = map[string]interface{}{
+	"MissingScopeProblem":    MissingScopeProblem{},
+	"InputValidationProblem": InputValidationProblem{},
+	"InputValidationIssue":   InputValidationIssue{},
+}
+
+// SchemaTypes maps the problem types to the name of their typed struct in Schemas
+var SchemaTypes = map[string]string{
+	TypeMissingScope: "MissingScopeProblem",
+	TypeBadRequest:   "InputValidationProblem",
+	TypeNotFound:     "InputValidationProblem",
+}
@@ -0,0 +1,163 @@
+package standard
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"tjdavis.dev/problems"
+)
+
+// SQLSTATE codes recognized by SQLStateMapper
+const (
+	SQLStateNotNullViolation     = "23502"
+	SQLStateForeignKeyViolation  = "23503"
+	SQLStateUniqueViolation      = "23505"
+	SQLStateSerializationFailure = "40001"
+)
+
+// SerializationRetryAfter is the Retry-After of the 503 problem of a
+// serialization failure
+var SerializationRetryAfter = time.Second
+
+// SQLStater is implemented by database driver errors that report their
+// SQLSTATE, such as those of pgx and lib/pq
+type SQLStater interface {
+	SQLState() string
+}
+
+// SQLError describes a database error.  Constraint, Table and Column are
+// empty when the driver doesn't report them.  Referenced reports that a
+// foreign key violation is the update or delete of a referenced row
+// rather than a reference to a missing row.
+type SQLError struct {
+	State      string
+	Message    string
+	Constraint string
+	Table      string
+	Column     string
+	Referenced bool
+}
+
+// SQLErrorFunc extracts the SQLError from an error chain and reports
+// whether it found one
+type SQLErrorFunc func(err error) (SQLError, bool)
+
+// SQLErrorOf is the default SQLErrorFunc.  It finds the first SQLStater
+// in the chain and reads the constraint, table and column from its
+// ConstraintName, TableName and ColumnName fields (pgx) or Constraint,
+// Table and Column fields (lib/pq) when it has them.
+//
+// SQLSTATE 23503 doesn't tell a referenced row from a missing one, so
+// Referenced is read from the message, which PostgreSQL only words as
+// "update or delete on" in English.  With lc_messages set to another
+// language every foreign key violation is taken as a missing reference;
+// use an SQLErrorFunc that sets Referenced, eg. from the table of the
+// error, to tell them apart.
+func SQLErrorOf(err error) (SQLError, bool) {
+	var stater SQLStater
+	if !errors.As(err, &stater) {
+		return SQLError{}, false
+	}
+	sqlErr := SQLError{State: stater.SQLState(), Message: err.Error()}
+	value := reflect.Indirect(reflect.ValueOf(stater))
+	if value.Kind() == reflect.Struct {
+		sqlErr.Constraint = stringField(value, "ConstraintName", "Constraint")
+		sqlErr.Table = stringField(value, "TableName", "Table")
+		sqlErr.Column = stringField(value, "ColumnName", "Column")
+	}
+	sqlErr.Referenced = sqlErr.State == SQLStateForeignKeyViolation &&
+		strings.Contains(sqlErr.Message, "update or delete on")
+	return sqlErr, true
+}
+
+// stringField returns the first of the named string fields of a struct
+func stringField(value reflect.Value, names ...string) string {
+	for _, name := range names {
+		if field := value.FieldByName(name); field.IsValid() && field.Kind() == reflect.String {
+			return field.String()
+		}
+	}
+	return ""
+}
+
+// SQLStateMapper creates the problems.ErrorMapper of database errors.  A
+// nil extract uses SQLErrorOf.  The problems have the error as their
+// cause, so errors.Is and errors.As still find it but the driver message,
+// which may hold SQL and data, isn't serialized.  They have a localized
+// detail:
+//
+//   - 23505 unique violation is a 409 TypeConflict with the constraint
+//     extension
+//   - 23503 foreign key violation is a 409 TypeConflict when a referenced
+//     row is updated or deleted, otherwise a 422 TypeUnprocessableEntity
+//     for a reference to a missing row; both have the constraint extension
+//   - 23502 not null violation is a 400 schema violation of the column,
+//     or of an unnamed value when the column isn't reported
+//   - 40001 serialization failure is a 503 that can be retried after
+//     SerializationRetryAfter
+//
+// Other errors are left to the next mapper.  The SQLStateMapper with
+// SQLErrorOf is registered with problems.DefaultMapperPriority.
+func SQLStateMapper(extract SQLErrorFunc) problems.ErrorMapper {
+	if extract == nil {
+		extract = SQLErrorOf
+	}
+	return func(err error) *problems.Problem {
+		sqlErr, ok := extract(err)
+		if !ok {
+			return nil
+		}
+		switch sqlErr.State {
+		case SQLStateUniqueViolation:
+			return constraintProblem(err, http.StatusConflict, TypeConflict, DetailUniqueViolation, sqlErr)
+		case SQLStateForeignKeyViolation:
+			if sqlErr.Referenced {
+				return constraintProblem(err, http.StatusConflict, TypeConflict, DetailForeignKeyReferenced, sqlErr)
+			}
+			return constraintProblem(err, http.StatusUnprocessableEntity, TypeUnprocessableEntity,
+				DetailForeignKeyMissing, sqlErr)
+		case SQLStateNotNullViolation:
+			key, params := DetailNotNullViolation, map[string]interface{}{"name": sqlErr.Column}
+			if sqlErr.Column == "" {
+				key, params = DetailNotNullUnnamed, nil
+			}
+			issue := problems.Issue{Type: TypeSchemaViolation, In: "body", Name: sqlErr.Column}
+			issue.SetDetailMessage(key, params)
+			prob := problems.New(http.StatusBadRequest, "")
+			_ = prob.Set("Type", TypeBadRequest)
+			_ = prob.Set("Title", http.StatusText(http.StatusBadRequest))
+			prob.SetDetailMessage(key, params)
+			prob.SetCause(err)
+			_ = prob.AddIssues(issue)
+			return prob
+		case SQLStateSerializationFailure:
+			prob := problems.New(http.StatusServiceUnavailable, "")
+			_ = prob.Set("Type", TypeServiceUnavailable)
+			_ = prob.Set("Title", http.StatusText(http.StatusServiceUnavailable))
+			prob.SetCause(err)
+			return setRetryAfter(prob, DetailServiceUnavailable, SerializationRetryAfter)
+		}
+		return nil
+	}
+}
+
+// constraintProblem creates the problem of a constraint violation with
+// the constraint extension
+func constraintProblem(err error, status int, typeURI, key string, sqlErr SQLError) *problems.Problem {
+	prob := problems.New(status, "")
+	_ = prob.Set("Type", typeURI)
+	_ = prob.Set("Title", http.StatusText(status))
+	prob.SetDetailMessage(key, nil)
+	prob.SetCause(err)
+	if sqlErr.Constraint != "" {
+		_ = prob.Set("constraint", sqlErr.Constraint)
+	}
+	return prob
+}
+
+func init() {
+	problems.RegisterErrorMapper(problems.DefaultMapperPriority, SQLStateMapper(nil))
+}
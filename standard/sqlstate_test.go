@@ -0,0 +1,106 @@
+package standard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"tjdavis.dev/problems"
+)
+
+// pgError mimics the error of a PostgreSQL driver
+type pgError struct {
+	Code           string
+	Message        string
+	ConstraintName string
+	ColumnName     string
+}
+
+func (e *pgError) Error() string    { return "ERROR: " + e.Message + " (SQLSTATE " + e.Code + ")" }
+func (e *pgError) SQLState() string { return e.Code }
+
+func TestSQLStateMapper(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		status     int
+		typ        string
+		constraint string
+	}{
+		{"Test unique", &pgError{Code: "23505", Message: `duplicate key value violates unique constraint "users_email_key"`,
+			ConstraintName: "users_email_key"}, http.StatusConflict, TypeConflict, "users_email_key"},
+		{"Test referenced", &pgError{Code: "23503", Message: `update or delete on table "users" violates foreign key constraint "orders_user_fkey" on table "orders"`,
+			ConstraintName: "orders_user_fkey"}, http.StatusConflict, TypeConflict, "orders_user_fkey"},
+		{"Test missing reference", &pgError{Code: "23503", Message: `insert or update on table "orders" violates foreign key constraint "orders_user_fkey"`,
+			ConstraintName: "orders_user_fkey"}, http.StatusUnprocessableEntity, TypeUnprocessableEntity, "orders_user_fkey"},
+		{"Test not null", fmt.Errorf("create user: %w", &pgError{Code: "23502", ColumnName: "email"}),
+			http.StatusBadRequest, TypeBadRequest, ""},
+		{"Test serialization", &pgError{Code: "40001"}, http.StatusServiceUnavailable, TypeServiceUnavailable, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prob := problems.FromError(tt.err)
+			if prob.Status != tt.status || prob.Type != tt.typ {
+				t.Fatalf("FromError() = %d %s, want %d %s", prob.Status, prob.Type, tt.status, tt.typ)
+			}
+			var pgErr *pgError
+			if !errors.As(prob, &pgErr) {
+				t.Errorf("FromError() doesn't wrap the driver error")
+			}
+			data, err := json.Marshal(prob)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			var members map[string]interface{}
+			if err := json.Unmarshal(data, &members); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if _, ok := members["error"]; ok {
+				t.Errorf("Marshal() = %s, want no error member", data)
+			}
+			if tt.constraint != "" && prob.Get("constraint") != tt.constraint {
+				t.Errorf("constraint = %v, want %v", prob.Get("constraint"), tt.constraint)
+			}
+		})
+	}
+
+	prob := problems.FromError(&pgError{Code: "23502", ColumnName: "email"})
+	if issues := prob.Issues(); len(issues) != 1 || issues[0].Name != "email" || prob.Detail != "email is required" {
+		t.Errorf("not null problem = %q %+v", prob.Detail, issues)
+	}
+	prob = problems.FromError(&pgError{Code: "23502"})
+	if issues := prob.Issues(); len(issues) != 1 || issues[0].Name != "" || prob.Detail != "A required value is missing" {
+		t.Errorf("unnamed not null problem = %q %+v", prob.Detail, issues)
+	}
+	prob = problems.FromError(&pgError{Code: "40001"})
+	if prob.Header().Get("Retry-After") != "1" {
+		t.Errorf("Retry-After = %v, want 1", prob.Header().Get("Retry-After"))
+	}
+	if prob := problems.FromError(&pgError{Code: "42P01"}); prob.Status != http.StatusInternalServerError {
+		t.Errorf("FromError() status = %d, want 500", prob.Status)
+	}
+}
+
+func TestSQLStateMapper_Extract(t *testing.T) {
+	errDuplicate := errors.New("duplicate")
+	mapper := SQLStateMapper(func(err error) (SQLError, bool) {
+		if errors.Is(err, errDuplicate) {
+			return SQLError{State: SQLStateUniqueViolation, Constraint: "sku"}, true
+		}
+		return SQLError{}, false
+	})
+	if prob := mapper(errDuplicate); prob == nil || prob.Status != http.StatusConflict || prob.Get("constraint") != "sku" {
+		t.Errorf("mapper() = %v", prob)
+	}
+	referenced := SQLStateMapper(func(err error) (SQLError, bool) {
+		return SQLError{State: SQLStateForeignKeyViolation, Message: "UPDATE ou DELETE sur la table", Referenced: true}, true
+	})
+	if prob := referenced(errDuplicate); prob == nil || prob.Status != http.StatusConflict {
+		t.Errorf("mapper() = %v, want 409", prob)
+	}
+	if prob := mapper(errors.New("other")); prob != nil {
+		t.Errorf("mapper() = %v, want nil", prob)
+	}
+}
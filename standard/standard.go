@@ -6,90 +6,114 @@ directory.
 package standard
 
 import (
-	"fmt"
 	"net/http"
 
-	"github.com/go-playground/locales/en"
-	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
-	en_translations "github.com/go-playground/validator/v10/translations/en"
-	. "tjdavis.dev/problems"
+	"tjdavis.dev/problems"
 )
 
 // Problem types
 const (
-	TypeNoAccessToken       = "urn:problem-type:noAccessToken"
-	TypeInvalidToken        = "urn:problem-type:invalidAccessToken"
-	TypeTokenExpired        = "urn:problem-type:expiredAccessToken"
-	TypeMissingScope        = "urn:problem-type:missingScope"
-	TypeMissingPermission   = "urn:problem-type:missingPermission"
-	TypeNotFound            = "urn:problem-type:resourceNotFound"
-	TypeBadRequest          = "urn:problem-type:badRequest"
-	TypeSchemaViolation     = "urn:problem-type:input-validation:schemaViolation"
-	TypeUnknownParameter    = "urn:problem-type:input-validation:unknownParameter"
-	TypeInternalServerError = "urn:problem-type:internalServerError"
-	TypeConflict            = "urn:problem-type:conflict"
+	TypeNoAccessToken        = "urn:problem-type:noAccessToken"
+	TypeInvalidToken         = "urn:problem-type:invalidAccessToken"
+	TypeTokenExpired         = "urn:problem-type:expiredAccessToken"
+	TypeMissingScope         = "urn:problem-type:missingScope"
+	TypeMissingPermission    = "urn:problem-type:missingPermission"
+	TypeNotFound             = "urn:problem-type:resourceNotFound"
+	TypeBadRequest           = problems.TypeBadRequest
+	TypeSchemaViolation      = problems.TypeSchemaViolation
+	TypeUnknownParameter     = problems.TypeUnknownParameter
+	TypeInternalServerError  = "urn:problem-type:internalServerError"
+	TypeConflict             = "urn:problem-type:conflict"
+	TypeMethodNotAllowed     = "urn:problem-type:methodNotAllowed"
+	TypeNotAcceptable        = "urn:problem-type:notAcceptable"
+	TypeGone                 = "urn:problem-type:gone"
+	TypePreconditionFailed   = "urn:problem-type:preconditionFailed"
+	TypePayloadTooLarge      = problems.TypePayloadTooLarge
+	TypeUnsupportedMediaType = problems.TypeUnsupportedMediaType
+	TypeUnprocessableEntity  = "urn:problem-type:unprocessableEntity"
+	TypePreconditionRequired = "urn:problem-type:preconditionRequired"
+	TypeTooManyRequests      = "urn:problem-type:tooManyRequests"
+	TypeNotImplemented       = "urn:problem-type:notImplemented"
+	TypeBadGateway           = "urn:problem-type:badGateway"
+	TypeServiceUnavailable   = "urn:problem-type:serviceUnavailable"
+	TypeGatewayTimeout       = "urn:problem-type:gatewayTimeout"
 )
 
-var validate *validator.Validate
-var uni *ut.UniversalTranslator
-var trans ut.Translator
-
-func init() {
-	validate = validator.New(validator.WithRequiredStructEnabled())
-	en := en.New()
-	uni = ut.New(en, en)
-	trans, _ = uni.GetTranslator("en")
-	_ = en_translations.RegisterDefaultTranslations(validate, trans)
-
-}
-
-func GetNoAccessResponse() *Problem {
-	prob := New(401, "No Bearer access token found in Authorization HTTP header")
+func GetNoAccessResponse() *problems.Problem {
+	prob := problems.New(401, "No Bearer access token found in Authorization HTTP header")
+	prob.SetDetailKey(DetailNoAccessToken)
 	prob.Type = TypeNoAccessToken
 	_ = prob.Set("Title", "No Access Token")
+	prob.Header().Set("WWW-Authenticate", Challenge{Realm: Realm}.String())
 	return prob
 }
 
-func GetInvalidTokenResponse() *Problem {
-	prob := New(401, "The Bearer access token found in the Authorization HTTP header is invalid")
+func GetInvalidTokenResponse() *problems.Problem {
+	prob := problems.New(401, "The Bearer access token found in the Authorization HTTP header is invalid")
+	prob.SetDetailKey(DetailInvalidToken)
 	_ = prob.Set("Title", "Invalid Access Token")
 	_ = prob.Set("Type", TypeInvalidToken)
+	prob.Header().Set("WWW-Authenticate", Challenge{
+		Realm:            Realm,
+		Error:            "invalid_token",
+		ErrorDescription: "The access token is invalid",
+	}.String())
 	return prob
 }
 
-func GetExpiredTokenResponse() *Problem {
-	prob := New(401, "The Bearer access token found in the Authorization HTTP header has expired")
+func GetExpiredTokenResponse() *problems.Problem {
+	prob := problems.New(401, "The Bearer access token found in the Authorization HTTP header has expired")
+	prob.SetDetailKey(DetailTokenExpired)
 	_ = prob.Set("Type", TypeTokenExpired)
 	_ = prob.Set("Title", "Expired Access Token")
+	prob.Header().Set("WWW-Authenticate", Challenge{
+		Realm:            Realm,
+		Error:            "invalid_token",
+		ErrorDescription: "The access token expired",
+	}.String())
 	return prob
 }
 
-func GetMissingScopeResponse(scopes []string) *Problem {
-	prob := New(403, "Forbidden to consult the resource")
+func GetMissingScopeResponse(scopes []string) *problems.Problem {
+	prob := problems.New(403, "Forbidden to consult the resource")
+	prob.SetDetailKey(DetailMissingScope)
 	_ = prob.Set("Type", TypeMissingScope)
 	_ = prob.Set("Title", "Missing Scope")
 
 	_ = prob.Set("requiredScopes", scopes)
+	prob.Header().Set("WWW-Authenticate", Challenge{
+		Realm: Realm,
+		Error: "insufficient_scope",
+		Scope: scopes,
+	}.String())
 	return prob
 }
 
-func GetMissingPermission() *Problem {
-	prob := New(403, "Not permitted to update the details of this resource")
+func GetMissingPermission() *problems.Problem {
+	prob := problems.New(403, "Not permitted to update the details of this resource")
+	prob.SetDetailKey(DetailMissingPermission)
 	_ = prob.Set("Type", TypeMissingPermission)
 	_ = prob.Set("Title", "Missing Permission")
 	return prob
 }
 
-func GetInternalErrorResponse(detail string) *Problem {
-	prob := New(http.StatusInternalServerError, detail)
+func GetInternalErrorResponse(detail string) *problems.Problem {
+	prob := problems.New(http.StatusInternalServerError, detail)
 	_ = prob.Set("Type", TypeInternalServerError)
 	_ = prob.Set("Title", http.StatusText(http.StatusInternalServerError))
 	return prob
 }
 
-func GetErrorResponseFromError(err error) *Problem {
-	prob := FromError(err)
+// GetErrorResponseFromError creates the problem of an error.  Errors
+// recognized by the error mappers of problems.MapError, including
+// problems, keep the problem they map to; any other error is a 500
+// internal server error.  A problem passed in isn't changed.
+func GetErrorResponseFromError(err error) *problems.Problem {
+	if prob := problems.MapError(err); prob != nil {
+		return prob
+	}
+	prob := problems.FromErrorWithStatus(http.StatusInternalServerError, err)
 	_ = prob.Set("Type", TypeInternalServerError)
 	return prob
 }
@@ -107,75 +131,72 @@ type MissingResourceParam struct {
 }
 
 // GetMissingResource creates a Problem that defines the resource that was not found
-func GetMissingResource(resource MissingResourceParam) *Problem {
-	prob := New(404, "Missing Resource")
+func GetMissingResource(resource MissingResourceParam) *problems.Problem {
+	params := map[string]interface{}{"type": resource.ResourceType, "value": resource.ResourceValue}
+	prob := problems.New(404, "Missing Resource")
 	_ = prob.Set("Type", TypeNotFound)
 	_ = prob.Set("Title", "Resource not found")
-	_ = prob.Set("Detail", fmt.Sprintf("No resource %s:%s found", resource.ResourceType, resource.ResourceValue))
+	prob.SetDetailMessage(DetailMissingResource, params)
 
-	issue := Problem{}
-	_ = issue.Set("Type", TypeNotFound)
-	if resource.Location != "" {
-		_ = issue.Set("in", resource.Location)
+	issue := problems.Issue{
+		Type:  TypeNotFound,
+		In:    resource.Location,
+		Name:  resource.ResourceType,
+		Value: resource.ResourceValue,
 	}
-	_ = issue.Set("name", resource.ResourceType)
-	_ = issue.Set("detail", fmt.Sprintf("the %s %v is not assigned", resource.ResourceType, resource.ResourceValue))
-	_ = issue.Set("value", resource.ResourceValue)
-	_ = prob.Set("issues", []interface{}{issue})
+	issue.SetDetailMessage(DetailResourceNotAssigned, params)
+	_ = prob.AddIssues(issue)
 	return prob
 }
 
 type ValidationParam struct {
 	Location  string      // path or body
 	Name      string      // the field name in error
+	Pointer   string      // the RFC 6901 JSON Pointer to the field in a body
 	Value     interface{} // the value that was provided
 	Issue     string      // the problem with the field
 	IsUnknown bool        // if the parameter is not defined by the API
+	Sensitive bool        // if the value must not be sent to the client
 }
 
-func GetInputValidationResponse(validations ...ValidationParam) *Problem {
-	prob := New(400, "The input message is incorrect; see issues for more information")
-	_ = prob.Set("Type", TypeBadRequest)
-	_ = prob.Set("Title", "Bad Request")
-
-	if len(validations) == 1 {
-		_ = prob.Set("Detail", validations[0].Issue)
+// ToIssue converts the validation to an input validation issue
+func (validation ValidationParam) ToIssue() problems.Issue {
+	issue := problems.Issue{
+		Type:      TypeSchemaViolation,
+		Detail:    validation.Issue,
+		In:        validation.Location,
+		Name:      validation.Name,
+		Pointer:   validation.Pointer,
+		Value:     validation.Value,
+		Sensitive: validation.Sensitive,
 	}
+	if validation.IsUnknown {
+		issue.Type = TypeUnknownParameter
+	}
+	return issue
+}
 
-	issues := make([]Problem, 0)
-
+// GetInputValidationResponse creates the bad request problem with an
+// issue for each validation; see problems.InputValidation.
+func GetInputValidationResponse(validations ...ValidationParam) *problems.Problem {
+	issues := make([]problems.Issue, 0, len(validations))
 	for _, validation := range validations {
-		issue := Problem{}
-		if validation.IsUnknown {
-			_ = issue.Set("Type", TypeUnknownParameter)
-		} else {
-			_ = issue.Set("Type", TypeSchemaViolation)
-		}
-		_ = issue.Set("in", validation.Location)
-		_ = issue.Set("name", validation.Name)
-		_ = issue.Set("value", validation.Value)
-		_ = issue.Set("Detail", validation.Issue)
-		issues = append(issues, issue)
+		issues = append(issues, validation.ToIssue())
 	}
-
-	_ = prob.Set("issues", issues)
-	return prob
+	return problems.InputValidation(issues...)
 }
 
-func GetValidatorResponse(err validator.ValidationErrors) *Problem {
-	var errors []ValidationParam
-	for _, err := range err {
-		param := ValidationParam{
-			Location: "body",
-			Name:     err.Namespace(),
-			Value:    err.Value(),
-			Issue:    err.Translate(trans),
-		}
-		errors = append(errors, param)
-	}
+// GetValidatorResponse builds an input validation problem with the issues
+// in English using the DefaultValidation.  Use DefaultValidation.ResponseFor
+// to describe the issues in the language of the request, and
+// FromValidationError to handle any error returned by the validator.
+func GetValidatorResponse(err validator.ValidationErrors) *problems.Problem {
+	return DefaultValidation.Response(problems.DefaultLocale, err)
+}
 
-	if len(errors) == 0 {
-		return nil
-	}
-	return GetInputValidationResponse(errors...)
+// FromValidationError converts any error returned by the validator to a
+// problem with the issues in English using the DefaultValidation; see
+// Validation.FromError.
+func FromValidationError(err error) *problems.Problem {
+	return DefaultValidation.FromError(problems.DefaultLocale, err)
 }
@@ -0,0 +1,34 @@
+package standard
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestGetErrorResponseFromError(t *testing.T) {
+	missingScope := GetMissingScopeResponse([]string{"users:read"})
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		typ    string
+	}{
+		{"Test mapped error", fmt.Errorf("q: %w", sql.ErrNoRows), http.StatusNotFound, ""},
+		{"Test problem", missingScope, http.StatusForbidden, TypeMissingScope},
+		{"Test other error", errors.New("boom"), http.StatusInternalServerError, TypeInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prob := GetErrorResponseFromError(tt.err)
+			if prob.Status != tt.status || prob.Type != tt.typ {
+				t.Errorf("GetErrorResponseFromError() = %d %q, want %d %q", prob.Status, prob.Type, tt.status, tt.typ)
+			}
+		})
+	}
+	if missingScope.Type != TypeMissingScope {
+		t.Errorf("GetErrorResponseFromError() changed the problem passed in to %v", missingScope.Type)
+	}
+}
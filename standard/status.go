@@ -0,0 +1,157 @@
+package standard
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"tjdavis.dev/problems"
+)
+
+// newStatusProblem creates a problem of one of the HTTP status types with
+// the detail of the message for key
+func newStatusProblem(status int, typeURI, key string, params map[string]interface{}) *problems.Problem {
+	prob := problems.New(status, http.StatusText(status))
+	_ = prob.Set("Type", typeURI)
+	_ = prob.Set("Title", http.StatusText(status))
+	prob.SetDetailMessage(key, params)
+	return prob
+}
+
+// setRetryAfter sets the detail with the retryAfter parameter, the
+// retryAfter member and the Retry-After header of a problem
+func setRetryAfter(prob *problems.Problem, key string, retryAfter time.Duration) *problems.Problem {
+	seconds := retrySeconds(retryAfter)
+	prob.SetDetailMessage(key, map[string]interface{}{"retryAfter": seconds, problems.PluralParam: seconds})
+	_ = prob.Set("retryAfter", seconds)
+	prob.Header().Set("Retry-After", strconv.Itoa(seconds))
+	return prob
+}
+
+// retrySeconds rounds a retry delay up to whole seconds, at least one
+func retrySeconds(retryAfter time.Duration) int {
+	seconds := int((retryAfter + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return seconds
+}
+
+// GetMethodNotAllowedResponse creates a 405 problem listing the methods
+// the resource supports in the allowedMethods member and the Allow header
+func GetMethodNotAllowedResponse(method string, allowed ...string) *problems.Problem {
+	prob := newStatusProblem(http.StatusMethodNotAllowed, TypeMethodNotAllowed, DetailMethodNotAllowed,
+		map[string]interface{}{"method": method, "allowed": strings.Join(allowed, ", ")})
+	_ = prob.Set("allowedMethods", allowed)
+	prob.Header().Set("Allow", strings.Join(allowed, ", "))
+	return prob
+}
+
+// GetNotAcceptableResponse creates a 406 problem listing the media types
+// the resource can be represented in
+func GetNotAcceptableResponse(supported ...string) *problems.Problem {
+	prob := newStatusProblem(http.StatusNotAcceptable, TypeNotAcceptable, DetailNotAcceptable,
+		map[string]interface{}{"supported": strings.Join(supported, ", ")})
+	_ = prob.Set("supportedMediaTypes", supported)
+	return prob
+}
+
+// GetConflictResponse creates a 409 problem.  The detail should say what
+// the request conflicts with.
+func GetConflictResponse(detail string) *problems.Problem {
+	prob := problems.New(http.StatusConflict, detail)
+	_ = prob.Set("Type", TypeConflict)
+	_ = prob.Set("Title", http.StatusText(http.StatusConflict))
+	return prob
+}
+
+// GetGoneResponse creates a 410 problem
+func GetGoneResponse() *problems.Problem {
+	return newStatusProblem(http.StatusGone, TypeGone, DetailGone, nil)
+}
+
+// GetPreconditionFailedResponse creates a 412 problem for a request whose
+// If-Match or If-Unmodified-Since precondition doesn't hold
+func GetPreconditionFailedResponse() *problems.Problem {
+	return newStatusProblem(http.StatusPreconditionFailed, TypePreconditionFailed, DetailPreconditionFailed, nil)
+}
+
+// GetPayloadTooLargeResponse creates a 413 problem with the largest body
+// accepted, in bytes, in the limit member
+func GetPayloadTooLargeResponse(limit int64) *problems.Problem {
+	prob := newStatusProblem(http.StatusRequestEntityTooLarge, TypePayloadTooLarge, DetailPayloadTooLarge,
+		map[string]interface{}{"limit": limit})
+	_ = prob.Set("limit", limit)
+	return prob
+}
+
+// GetUnsupportedMediaTypeResponse creates a 415 problem listing the media
+// types the operation accepts, which are also sent in the Accept header
+func GetUnsupportedMediaTypeResponse(supported ...string) *problems.Problem {
+	prob := newStatusProblem(http.StatusUnsupportedMediaType, TypeUnsupportedMediaType, DetailUnsupportedMediaType,
+		map[string]interface{}{"supported": strings.Join(supported, ", ")})
+	_ = prob.Set("supportedMediaTypes", supported)
+	prob.Header().Set("Accept", strings.Join(supported, ", "))
+	return prob
+}
+
+// GetUnsupportedPatchResponse creates the 415 problem of a PATCH request
+// with a patch document format the resource doesn't support.  The
+// supported formats are sent in the Accept-Patch header (RFC 5789).
+func GetUnsupportedPatchResponse(supported ...string) *problems.Problem {
+	prob := newStatusProblem(http.StatusUnsupportedMediaType, TypeUnsupportedMediaType, DetailUnsupportedMediaType,
+		map[string]interface{}{"supported": strings.Join(supported, ", ")})
+	_ = prob.Set("supportedMediaTypes", supported)
+	prob.Header().Set("Accept-Patch", strings.Join(supported, ", "))
+	return prob
+}
+
+// GetUnprocessableEntityResponse creates a 422 problem for a well formed
+// request that breaks a business rule described by the detail
+func GetUnprocessableEntityResponse(detail string) *problems.Problem {
+	prob := problems.New(http.StatusUnprocessableEntity, detail)
+	_ = prob.Set("Type", TypeUnprocessableEntity)
+	_ = prob.Set("Title", http.StatusText(http.StatusUnprocessableEntity))
+	return prob
+}
+
+// GetPreconditionRequiredResponse creates a 428 problem listing the
+// conditional headers the operation requires, eg. If-Match
+func GetPreconditionRequiredResponse(headers ...string) *problems.Problem {
+	prob := newStatusProblem(http.StatusPreconditionRequired, TypePreconditionRequired, DetailPreconditionRequired,
+		map[string]interface{}{"headers": strings.Join(headers, ", ")})
+	_ = prob.Set("requiredHeaders", headers)
+	return prob
+}
+
+// GetTooManyRequestsResponse creates a 429 problem with the delay before
+// retrying, rounded up to whole seconds, in the retryAfter member and the
+// Retry-After header
+func GetTooManyRequestsResponse(retryAfter time.Duration) *problems.Problem {
+	return setRetryAfter(newStatusProblem(http.StatusTooManyRequests, TypeTooManyRequests, "", nil), DetailTooManyRequests, retryAfter)
+}
+
+// GetNotImplementedResponse creates a 501 problem
+func GetNotImplementedResponse() *problems.Problem {
+	return newStatusProblem(http.StatusNotImplemented, TypeNotImplemented, DetailNotImplemented, nil)
+}
+
+// GetBadGatewayResponse creates a 502 problem for an invalid response from
+// an upstream server
+func GetBadGatewayResponse() *problems.Problem {
+	return newStatusProblem(http.StatusBadGateway, TypeBadGateway, DetailBadGateway, nil)
+}
+
+// GetServiceUnavailableResponse creates a 503 problem with the delay
+// before retrying, rounded up to whole seconds, in the retryAfter member
+// and the Retry-After header
+func GetServiceUnavailableResponse(retryAfter time.Duration) *problems.Problem {
+	return setRetryAfter(newStatusProblem(http.StatusServiceUnavailable, TypeServiceUnavailable, "", nil), DetailServiceUnavailable, retryAfter)
+}
+
+// GetGatewayTimeoutResponse creates a 504 problem for an upstream server
+// that didn't respond in time
+func GetGatewayTimeoutResponse() *problems.Problem {
+	return newStatusProblem(http.StatusGatewayTimeout, TypeGatewayTimeout, DetailGatewayTimeout, nil)
+}
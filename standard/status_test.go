@@ -0,0 +1,69 @@
+package standard
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"tjdavis.dev/problems"
+)
+
+func TestStatusResponses(t *testing.T) {
+	tests := []struct {
+		name      string
+		prob      *problems.Problem
+		status    int
+		typeURI   string
+		extension string
+		value     interface{}
+		detail    string
+	}{
+		{"Test 405", GetMethodNotAllowedResponse("DELETE", "GET", "HEAD"), 405, TypeMethodNotAllowed,
+			"allowedMethods", []string{"GET", "HEAD"}, "The method DELETE is not allowed; use GET, HEAD"},
+		{"Test 406", GetNotAcceptableResponse("application/json"), 406, TypeNotAcceptable,
+			"supportedMediaTypes", []string{"application/json"}, "None of the accepted media types can be produced; use application/json"},
+		{"Test 409", GetConflictResponse("The user already exists"), 409, TypeConflict,
+			"", nil, "The user already exists"},
+		{"Test 410", GetGoneResponse(), 410, TypeGone, "", nil, "The resource is no longer available"},
+		{"Test 412", GetPreconditionFailedResponse(), 412, TypePreconditionFailed,
+			"", nil, "The resource has been changed since it was last retrieved"},
+		{"Test 413", GetPayloadTooLargeResponse(1024), 413, TypePayloadTooLarge,
+			"limit", int64(1024), "The request body is larger than 1024 bytes"},
+		{"Test 415", GetUnsupportedMediaTypeResponse("application/json", "application/xml"), 415, TypeUnsupportedMediaType,
+			"supportedMediaTypes", []string{"application/json", "application/xml"},
+			"The media type of the request body is not supported; use application/json, application/xml"},
+		{"Test 422", GetUnprocessableEntityResponse("The order is already shipped"), 422, TypeUnprocessableEntity,
+			"", nil, "The order is already shipped"},
+		{"Test 428", GetPreconditionRequiredResponse("If-Match"), 428, TypePreconditionRequired,
+			"requiredHeaders", []string{"If-Match"}, "The request must be conditional; use If-Match"},
+		{"Test 429", GetTooManyRequestsResponse(1500 * time.Millisecond), 429, TypeTooManyRequests,
+			"retryAfter", 2, "Too many requests; retry after 2 seconds"},
+		{"Test 501", GetNotImplementedResponse(), 501, TypeNotImplemented, "", nil, "The operation is not implemented"},
+		{"Test 502", GetBadGatewayResponse(), 502, TypeBadGateway, "", nil, "An upstream server returned an invalid response"},
+		{"Test 503", GetServiceUnavailableResponse(0), 503, TypeServiceUnavailable,
+			"retryAfter", 1, "The service is unavailable; retry after 1 second"},
+		{"Test 504", GetGatewayTimeoutResponse(), 504, TypeGatewayTimeout, "", nil, "An upstream server did not respond in time"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.prob.Status != tt.status || tt.prob.Type != tt.typeURI || tt.prob.Detail != tt.detail {
+				t.Errorf("got %d %s %q, want %d %s %q", tt.prob.Status, tt.prob.Type, tt.prob.Detail,
+					tt.status, tt.typeURI, tt.detail)
+			}
+			if tt.extension != "" && !reflect.DeepEqual(tt.prob.Get(tt.extension), tt.value) {
+				t.Errorf("%s = %#v, want %#v", tt.extension, tt.prob.Get(tt.extension), tt.value)
+			}
+			info, ok := problems.LookupType(tt.typeURI)
+			if !ok || info.Status != tt.status {
+				t.Errorf("%s is registered with status %d, want %d", tt.typeURI, info.Status, tt.status)
+			}
+		})
+	}
+}
+
+func TestGetServiceUnavailableResponse_Localize(t *testing.T) {
+	prob := GetServiceUnavailableResponse(30*time.Second).Localize("de", nil)
+	if prob.Title != "Dienst nicht verfügbar" || prob.Detail != "Der Dienst ist nicht verfügbar; wiederholen Sie nach 30 Sekunden" {
+		t.Errorf("Localize() = %q %q", prob.Title, prob.Detail)
+	}
+}
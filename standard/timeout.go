@@ -0,0 +1,183 @@
+package standard
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"tjdavis.dev/problems"
+)
+
+// TimeoutRetryAfter is the default Retry-After of the problem of a
+// request that timed out on the server
+var TimeoutRetryAfter = 5 * time.Second
+
+// IsClientDisconnect reports whether err is the context.Canceled of a
+// client that closed the connection before the response was written.
+// These aren't server errors and usually aren't worth logging as such.
+func IsClientDisconnect(r *http.Request, err error) bool {
+	return errors.Is(err, context.Canceled) && errors.Is(r.Context().Err(), context.Canceled)
+}
+
+// FromContextError converts a context error returned while handling the
+// request to a problem, or returns nil for other errors:
+//
+//   - a client disconnect is a 499 Client Closed Request; see
+//     IsClientDisconnect
+//   - the request context reaching its deadline, eg. the one set by
+//     Timeout, is a 503 that can be retried after TimeoutRetryAfter
+//   - any other deadline, eg. of a call to an upstream server, is a 504
+//   - any other cancellation is a 500
+//
+// The problems wrap the error.
+func FromContextError(r *http.Request, err error) *problems.Problem {
+	switch {
+	case IsClientDisconnect(r, err):
+		prob := problems.FromErrorWithStatus(problems.StatusClientClosedRequest, err)
+		prob.Title = "Client Closed Request"
+		prob.SetDetailKey(problems.DetailCanceled)
+		return prob
+	case errors.Is(err, context.DeadlineExceeded) && errors.Is(r.Context().Err(), context.DeadlineExceeded):
+		return contextProblem(http.StatusServiceUnavailable, err, TimeoutRetryAfter)
+	case errors.Is(err, context.DeadlineExceeded):
+		return contextProblem(http.StatusGatewayTimeout, err, 0)
+	case errors.Is(err, context.Canceled):
+		return problems.FromErrorWithStatus(http.StatusInternalServerError, err)
+	}
+	return nil
+}
+
+// contextProblem creates the 503 or 504 problem of a timeout wrapping
+// err, with a Retry-After unless retryAfter is zero
+func contextProblem(status int, err error, retryAfter time.Duration) *problems.Problem {
+	typeURI, key := TypeServiceUnavailable, DetailServiceUnavailable
+	if status == http.StatusGatewayTimeout {
+		typeURI, key = TypeGatewayTimeout, DetailGatewayTimeout
+	}
+	prob := problems.FromErrorWithStatus(status, err)
+	_ = prob.Set("Type", typeURI)
+	_ = prob.Set("Title", http.StatusText(status))
+	if retryAfter > 0 {
+		return setRetryAfter(prob, key, retryAfter)
+	}
+	prob.SetDetailMessage(key, nil)
+	return prob
+}
+
+// TimeoutOption configures the Timeout middleware
+type TimeoutOption func(*timeoutOptions)
+
+type timeoutOptions struct {
+	status     int
+	retryAfter time.Duration
+}
+
+// TimeoutStatus sets the status of the timeout problem, either
+// http.StatusServiceUnavailable (the default) or http.StatusGatewayTimeout
+func TimeoutStatus(status int) TimeoutOption {
+	return func(o *timeoutOptions) {
+		o.status = status
+	}
+}
+
+// TimeoutRetry sets the Retry-After of the timeout problem instead of
+// TimeoutRetryAfter
+func TimeoutRetry(retryAfter time.Duration) TimeoutOption {
+	return func(o *timeoutOptions) {
+		o.retryAfter = retryAfter
+	}
+}
+
+// Timeout is middleware that cancels the request context after d.  A
+// handler that hasn't finished by then is answered with a 503 problem, or
+// the status set with TimeoutStatus, and a Retry-After header; it should
+// return once its context is done, and its writes fail with
+// http.ErrHandlerTimeout.  Nothing is written to a client that has
+// disconnected.
+//
+// Like http.TimeoutHandler the response is buffered until the handler
+// returns, so it doesn't support streaming.
+func Timeout(d time.Duration, opts ...TimeoutOption) func(http.Handler) http.Handler {
+	options := timeoutOptions{status: http.StatusServiceUnavailable, retryAfter: TimeoutRetryAfter}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			tw := &timeoutWriter{header: make(http.Header)}
+			done := make(chan struct{})
+			panicked := make(chan interface{}, 1)
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						panicked <- p
+					}
+				}()
+				next.ServeHTTP(tw, r.WithContext(ctx))
+				close(done)
+			}()
+			select {
+			case p := <-panicked:
+				panic(p)
+			case <-done:
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+				for key, values := range tw.header {
+					w.Header()[key] = values
+				}
+				if tw.code == 0 {
+					tw.code = http.StatusOK
+				}
+				w.WriteHeader(tw.code)
+				_, _ = w.Write(tw.buf.Bytes())
+			case <-ctx.Done():
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+				tw.timedOut = true
+				if IsClientDisconnect(r, ctx.Err()) {
+					return
+				}
+				_ = contextProblem(options.status, ctx.Err(), options.retryAfter).Render(w, r)
+			}
+		})
+	}
+}
+
+// timeoutWriter buffers the response of a handler run by Timeout
+type timeoutWriter struct {
+	mu       sync.Mutex
+	header   http.Header
+	buf      bytes.Buffer
+	code     int
+	timedOut bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) Write(p []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if tw.code == 0 {
+		tw.code = http.StatusOK
+	}
+	return tw.buf.Write(p)
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.code != 0 {
+		return
+	}
+	tw.code = code
+}
@@ -0,0 +1,123 @@
+package standard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"tjdavis.dev/problems"
+)
+
+func TestTimeout(t *testing.T) {
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		_, _ = io.WriteString(w, "late")
+	})
+	fast := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Test", "yes")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, "done")
+	})
+	tests := []struct {
+		name       string
+		handler    http.Handler
+		opts       []TimeoutOption
+		status     int
+		typ        string
+		retryAfter string
+	}{
+		{"Test timeout", slow, nil, http.StatusServiceUnavailable, TypeServiceUnavailable, "5"},
+		{"Test gateway timeout", slow, []TimeoutOption{TimeoutStatus(http.StatusGatewayTimeout), TimeoutRetry(time.Minute)},
+			http.StatusGatewayTimeout, TypeGatewayTimeout, "60"},
+		{"Test in time", fast, nil, http.StatusCreated, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			Timeout(10*time.Millisecond, tt.opts...)(tt.handler).ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if got := w.Header().Get("Retry-After"); got != tt.retryAfter {
+				t.Errorf("Retry-After = %q, want %q", got, tt.retryAfter)
+			}
+			if tt.typ == "" {
+				if w.Body.String() != "done" || w.Header().Get("X-Test") != "yes" {
+					t.Errorf("response = %q %v", w.Body.String(), w.Header())
+				}
+				return
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body["type"] != tt.typ {
+				t.Errorf("type = %v, want %v", body["type"], tt.typ)
+			}
+		})
+	}
+}
+
+func TestTimeout_ClientDisconnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		<-r.Context().Done()
+	})
+	w := httptest.NewRecorder()
+	Timeout(time.Second)(handler).ServeHTTP(w, httptest.NewRequest("GET", "/", nil).WithContext(ctx))
+	if w.Body.Len() != 0 {
+		t.Errorf("body = %q, want nothing written", w.Body.String())
+	}
+}
+
+func TestFromContextError(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		err        error
+		status     int
+		disconnect bool
+	}{
+		{"Test client disconnect", canceled, fmt.Errorf("query: %w", context.Canceled), problems.StatusClientClosedRequest, true},
+		{"Test server timeout", expired, context.DeadlineExceeded, http.StatusServiceUnavailable, false},
+		{"Test upstream timeout", context.Background(), context.DeadlineExceeded, http.StatusGatewayTimeout, false},
+		{"Test canceled", context.Background(), context.Canceled, http.StatusInternalServerError, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil).WithContext(tt.ctx)
+			if got := IsClientDisconnect(r, tt.err); got != tt.disconnect {
+				t.Errorf("IsClientDisconnect() = %v, want %v", got, tt.disconnect)
+			}
+			prob := FromContextError(r, tt.err)
+			if prob == nil || prob.Status != tt.status {
+				t.Fatalf("FromContextError() = %v, want %d", prob, tt.status)
+			}
+			if !errors.Is(prob, tt.err) {
+				t.Errorf("FromContextError() doesn't wrap %v", tt.err)
+			}
+		})
+	}
+	if prob := FromContextError(httptest.NewRequest("GET", "/", nil), errors.New("other")); prob != nil {
+		t.Errorf("FromContextError() = %v, want nil", prob)
+	}
+}
+
+func TestTimeoutWriter(t *testing.T) {
+	tw := &timeoutWriter{header: make(http.Header)}
+	tw.timedOut = true
+	if _, err := tw.Write([]byte("late")); !errors.Is(err, http.ErrHandlerTimeout) {
+		t.Errorf("Write() error = %v, want http.ErrHandlerTimeout", err)
+	}
+}
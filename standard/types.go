@@ -0,0 +1,30 @@
+package standard
+
+import (
+	"bytes"
+	_ "embed"
+
+	"tjdavis.dev/problems"
+)
+
+// TypesJSON is the problem type file documenting the standard problem
+// types.  It uses the same format as problems.Registry.LoadFile so
+// additional types can be kept alongside it.
+//
+//go:embed types.json
+var TypesJSON []byte
+
+// Types returns the documentation of the standard problem types
+func Types() []problems.TypeInfo {
+	file, err := problems.ReadTypeFile(bytes.NewReader(TypesJSON))
+	if err != nil {
+		panic(err)
+	}
+	return file.Types
+}
+
+func init() {
+	if err := problems.DefaultRegistry.Load(bytes.NewReader(TypesJSON)); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,67 @@
+package standard
+
+import (
+	"testing"
+
+	"tjdavis.dev/problems"
+)
+
+// Every type constant must be documented in types.json
+func TestTypes(t *testing.T) {
+	constants := []string{
+		TypeNoAccessToken,
+		TypeInvalidToken,
+		TypeTokenExpired,
+		TypeMissingScope,
+		TypeMissingPermission,
+		TypeNotFound,
+		TypeBadRequest,
+		TypeSchemaViolation,
+		TypeUnknownParameter,
+		TypeInternalServerError,
+		TypeConflict,
+		TypeMethodNotAllowed,
+		TypeNotAcceptable,
+		TypeGone,
+		TypePreconditionFailed,
+		TypePayloadTooLarge,
+		TypeUnsupportedMediaType,
+		TypeUnprocessableEntity,
+		TypePreconditionRequired,
+		TypeTooManyRequests,
+		TypeNotImplemented,
+		TypeBadGateway,
+		TypeServiceUnavailable,
+		TypeGatewayTimeout,
+	}
+	documented := make(map[string]bool)
+	for _, info := range Types() {
+		documented[info.Type] = true
+	}
+	for _, typeURI := range constants {
+		if !documented[typeURI] {
+			t.Errorf("%s is not documented in types.json", typeURI)
+		}
+		if _, ok := problems.LookupType(typeURI); !ok {
+			t.Errorf("%s is not registered", typeURI)
+		}
+	}
+	if len(documented) != len(constants) {
+		t.Errorf("types.json documents %d types, want %d", len(documented), len(constants))
+	}
+}
+
+func TestGetMissingResource(t *testing.T) {
+	prob := GetMissingResource(MissingResourceParam{ResourceType: "User", ResourceValue: 123, Location: "path"})
+	if prob.Detail != "No resource User:123 found" {
+		t.Errorf("GetMissingResource() detail = %v", prob.Detail)
+	}
+	localized := prob.Localize("de", nil)
+	if localized.Detail != "Keine Ressource User:123 gefunden" {
+		t.Errorf("GetMissingResource() German detail = %v", localized.Detail)
+	}
+	issue := localized.Issues()[0]
+	if issue.Detail != "User 123 ist nicht vergeben" {
+		t.Errorf("GetMissingResource() German issue detail = %v", issue.Detail)
+	}
+}
@@ -0,0 +1,343 @@
+package standard
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/ar"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/es"
+	"github.com/go-playground/locales/fa"
+	"github.com/go-playground/locales/fr"
+	"github.com/go-playground/locales/id"
+	"github.com/go-playground/locales/it"
+	"github.com/go-playground/locales/ja"
+	"github.com/go-playground/locales/lv"
+	"github.com/go-playground/locales/nl"
+	"github.com/go-playground/locales/pl"
+	"github.com/go-playground/locales/pt"
+	"github.com/go-playground/locales/pt_BR"
+	"github.com/go-playground/locales/ru"
+	"github.com/go-playground/locales/tr"
+	"github.com/go-playground/locales/uk"
+	"github.com/go-playground/locales/vi"
+	"github.com/go-playground/locales/zh"
+	"github.com/go-playground/locales/zh_Hant_TW"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	ar_translations "github.com/go-playground/validator/v10/translations/ar"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	es_translations "github.com/go-playground/validator/v10/translations/es"
+	fa_translations "github.com/go-playground/validator/v10/translations/fa"
+	fr_translations "github.com/go-playground/validator/v10/translations/fr"
+	id_translations "github.com/go-playground/validator/v10/translations/id"
+	it_translations "github.com/go-playground/validator/v10/translations/it"
+	ja_translations "github.com/go-playground/validator/v10/translations/ja"
+	lv_translations "github.com/go-playground/validator/v10/translations/lv"
+	nl_translations "github.com/go-playground/validator/v10/translations/nl"
+	pl_translations "github.com/go-playground/validator/v10/translations/pl"
+	pt_translations "github.com/go-playground/validator/v10/translations/pt"
+	pt_BR_translations "github.com/go-playground/validator/v10/translations/pt_BR"
+	ru_translations "github.com/go-playground/validator/v10/translations/ru"
+	tr_translations "github.com/go-playground/validator/v10/translations/tr"
+	uk_translations "github.com/go-playground/validator/v10/translations/uk"
+	vi_translations "github.com/go-playground/validator/v10/translations/vi"
+	zh_translations "github.com/go-playground/validator/v10/translations/zh"
+	zh_tw_translations "github.com/go-playground/validator/v10/translations/zh_tw"
+	"tjdavis.dev/problems"
+)
+
+type validatorLocale struct {
+	locale   locales.Translator
+	register func(*validator.Validate, ut.Translator) error
+}
+
+// validatorLocales are the languages with translations supplied by
+// go-playground/validator
+var validatorLocales = []validatorLocale{
+	{en.New(), en_translations.RegisterDefaultTranslations},
+	{ar.New(), ar_translations.RegisterDefaultTranslations},
+	{es.New(), es_translations.RegisterDefaultTranslations},
+	{fa.New(), fa_translations.RegisterDefaultTranslations},
+	{fr.New(), fr_translations.RegisterDefaultTranslations},
+	{id.New(), id_translations.RegisterDefaultTranslations},
+	{it.New(), it_translations.RegisterDefaultTranslations},
+	{ja.New(), ja_translations.RegisterDefaultTranslations},
+	{lv.New(), lv_translations.RegisterDefaultTranslations},
+	{nl.New(), nl_translations.RegisterDefaultTranslations},
+	{pl.New(), pl_translations.RegisterDefaultTranslations},
+	{pt.New(), pt_translations.RegisterDefaultTranslations},
+	{pt_BR.New(), pt_BR_translations.RegisterDefaultTranslations},
+	{ru.New(), ru_translations.RegisterDefaultTranslations},
+	{tr.New(), tr_translations.RegisterDefaultTranslations},
+	{uk.New(), uk_translations.RegisterDefaultTranslations},
+	{vi.New(), vi_translations.RegisterDefaultTranslations},
+	{zh.New(), zh_translations.RegisterDefaultTranslations},
+	{zh_Hant_TW.New(), zh_tw_translations.RegisterDefaultTranslations},
+}
+
+// Validation pairs a validator with the translators used to describe its
+// errors in each language.  Create one with NewValidation and replace
+// DefaultValidation, or pass it where a response is built.
+type Validation struct {
+	// Validate is the validator the translations are registered with
+	Validate *validator.Validate
+	uni      *ut.UniversalTranslator
+	locales  []string
+	// sensitive holds the sensitiveKey of each field tagged as sensitive
+	sensitive sync.Map
+	// issueTypes holds the issueType of each tag with its own issue type
+	issueTypes sync.Map
+}
+
+// issueType is the type of the issues reported for a validation tag
+type issueType struct {
+	typeURI string
+	extend  func(validator.FieldError, *problems.Issue)
+}
+
+// sensitiveKey identifies a struct field in a validation error, which
+// doesn't carry the struct the field belongs to.  Fields with the same
+// name and type as a sensitive field are masked too.
+type sensitiveKey struct {
+	name string
+	typ  reflect.Type
+}
+
+// DefaultValidation is used by GetValidatorResponse.  Its Locales limit
+// the languages problems are rendered in, see problems.AvailableLocales,
+// so a Validation used for responses should replace it.
+var DefaultValidation *Validation
+
+func init() {
+	var err error
+	DefaultValidation, err = NewValidation(validator.New(validator.WithRequiredStructEnabled()))
+	if err != nil {
+		panic(err)
+	}
+	problems.DefaultValidator = func(r *http.Request, dst interface{}) error {
+		return DefaultValidation.ValidateRequest(r, dst)
+	}
+	problems.RegisterLocaleSource(func() []string {
+		return DefaultValidation.Locales()
+	})
+}
+
+// NewValidation registers the translations of every language supplied by
+// go-playground/validator with validate.  English is the fallback.
+// FieldName is registered as the validator's tag name function so issues
+// are named by the parameter or json names of the fields.  The values of
+// fields with a `problem:"sensitive"` tag are masked in the issues.
+func NewValidation(validate *validator.Validate) (*Validation, error) {
+	fallback := validatorLocales[0].locale
+	all := make([]locales.Translator, 0, len(validatorLocales))
+	for _, vl := range validatorLocales {
+		all = append(all, vl.locale)
+	}
+	v := &Validation{
+		Validate: validate,
+		uni:      ut.New(fallback, all...),
+	}
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		if problems.IsSensitiveField(fld) {
+			v.sensitive.Store(sensitiveKey{fld.Name, fld.Type}, true)
+		}
+		return FieldName(fld)
+	})
+	for _, vl := range validatorLocales {
+		trans, _ := v.uni.GetTranslator(vl.locale.Locale())
+		if err := vl.register(validate, trans); err != nil {
+			return nil, problems.FromError(err)
+		}
+		v.locales = append(v.locales, vl.locale.Locale())
+	}
+	return v, nil
+}
+
+// Locales lists the languages with validator translations
+func (v *Validation) Locales() []string {
+	return v.locales
+}
+
+// AddLocale adds a language that go-playground/validator doesn't supply
+// translations for.  register may be nil, in which case only the messages
+// added with RegisterTagMessage are translated.
+func (v *Validation) AddLocale(locale locales.Translator, register func(*validator.Validate, ut.Translator) error) error {
+	if err := v.uni.AddTranslator(locale, true); err != nil {
+		return problems.FromError(err)
+	}
+	if register != nil {
+		trans, _ := v.uni.GetTranslator(locale.Locale())
+		if err := register(v.Validate, trans); err != nil {
+			return problems.FromError(err)
+		}
+	}
+	v.locales = append(v.locales, locale.Locale())
+	return nil
+}
+
+// Translator returns the translator for a language tag or Accept-Language
+// header, negotiating the closest available language so `pt-PT` uses `pt`
+// and unknown languages use English.  Only the problems.AvailableLocales
+// are negotiated, so the issues are in the language of the problem.
+func (v *Validation) Translator(locale string) ut.Translator {
+	locale = problems.NegotiateLocale(locale, problems.AvailableLocales(), problems.DefaultLocale)
+	best := problems.NegotiateLocale(locale, v.locales, problems.DefaultLocale)
+	trans, _ := v.uni.GetTranslator(best)
+	return trans
+}
+
+// RegisterTagMessage registers the message for a validation tag in one of
+// the Locales.  As with the validator's own translations {0} is replaced
+// with the field name and {1} with the tag's parameter.
+func (v *Validation) RegisterTagMessage(tag, locale, text string) error {
+	trans, ok := v.uni.GetTranslator(locale)
+	if !ok {
+		return problems.Errorf(500, "No validator translations are available for %s", locale)
+	}
+	err := v.Validate.RegisterTranslation(tag, trans,
+		func(ut ut.Translator) error {
+			return ut.Add(tag, text, true)
+		},
+		func(ut ut.Translator, fe validator.FieldError) string {
+			message, err := ut.T(tag, fe.Field(), fe.Param())
+			if err != nil {
+				return fe.Error()
+			}
+			return message
+		})
+	if err != nil {
+		return problems.FromError(err)
+	}
+	return nil
+}
+
+// RegisterIssueType reports the failures of a validation tag, including
+// the tags reported by struct-level validations, as issues of typeURI in
+// place of schema violations.  extend, which may be nil, can add extension
+// members to the issue:
+//
+//	v.Validate.RegisterStructValidation(func(sl validator.StructLevel) {
+//		booking := sl.Current().Interface().(Booking)
+//		if booking.Period.EndDate.Before(booking.Period.StartDate) {
+//			sl.ReportError(booking.Period, "period", "Period", "invalidPeriod", "")
+//		}
+//	}, Booking{})
+//	v.RegisterIssueType("invalidPeriod", "urn:problem-type:input-validation:invalidPeriod", nil)
+//	v.RegisterTagMessage("invalidPeriod", "en", "endDate should be after startDate")
+func (v *Validation) RegisterIssueType(tag, typeURI string, extend func(fe validator.FieldError, issue *problems.Issue)) {
+	v.issueTypes.Store(tag, issueType{typeURI, extend})
+}
+
+// Issue converts a validation error to an issue of the body described in
+// the language of the translator
+func (v *Validation) Issue(fe validator.FieldError, trans ut.Translator) problems.Issue {
+	issue := problems.Issue{
+		Type:      TypeSchemaViolation,
+		Detail:    fe.Translate(trans),
+		In:        "body",
+		Name:      JSONName(fe.Namespace()),
+		Pointer:   JSONPointer(fe.Namespace()),
+		Value:     fe.Value(),
+		Sensitive: v.isSensitive(fe),
+	}
+	if it, ok := v.issueTypes.Load(fe.Tag()); ok {
+		it := it.(issueType)
+		issue.Type = it.typeURI
+		if it.extend != nil {
+			it.extend(fe, &issue)
+		}
+	}
+	return issue
+}
+
+// Response builds the input validation problem for the validation errors
+// with the issues described in the language, which may be a language tag
+// or an Accept-Language header.  nil is returned if there are no errors.
+func (v *Validation) Response(locale string, errs validator.ValidationErrors) *problems.Problem {
+	if len(errs) == 0 {
+		return nil
+	}
+	trans := v.Translator(locale)
+	issues := make([]problems.Issue, 0, len(errs))
+	for _, fe := range errs {
+		issues = append(issues, v.Issue(fe, trans))
+	}
+	return problems.InputValidation(issues...)
+}
+
+// FromError converts any error returned by the validator to a problem
+// with the issues described in the language, which may be a language tag
+// or an Accept-Language header:
+//
+//   - validation errors are an input validation problem
+//   - a problem, eg. returned by a custom validation, is returned as is
+//   - an InvalidValidationError, from validating nil or a value that isn't
+//     a struct, and empty validation errors are programmer errors, so they
+//     are an internal server error
+//   - other errors are an internal server error
+//
+// nil is returned for a nil error.
+func (v *Validation) FromError(locale string, err error) *problems.Problem {
+	if err == nil {
+		return nil
+	}
+	var errs validator.ValidationErrors
+	var invalid *validator.InvalidValidationError
+	var prob *problems.Problem
+	switch {
+	case errors.As(err, &errs) && len(errs) > 0:
+		return v.Response(locale, errs)
+	case errors.As(err, &invalid):
+		return validationFailure(err)
+	case errors.As(err, &prob):
+		return prob
+	case errors.As(err, &errs):
+		return GetInternalErrorResponse("The validation failed without reporting any errors")
+	default:
+		return validationFailure(err)
+	}
+}
+
+// validationFailure is the internal server error of an error that kept
+// the input from being validated.  The error is the cause, for logging,
+// but its text, which may describe the internals, isn't serialized.
+func validationFailure(err error) *problems.Problem {
+	prob := GetInternalErrorResponse("Unable to validate the input")
+	prob.SetCause(fmt.Errorf("validate the input: %w", err))
+	return prob
+}
+
+// isSensitive reports whether the field in error is tagged as sensitive
+func (v *Validation) isSensitive(err validator.FieldError) bool {
+	_, ok := v.sensitive.Load(sensitiveKey{err.StructField(), err.Type()})
+	return ok
+}
+
+// ResponseFor builds the input validation problem with the issues in the
+// best language for the request's Accept-Language header
+func (v *Validation) ResponseFor(r *http.Request, errs validator.ValidationErrors) *problems.Problem {
+	return v.Response(r.Header.Get("Accept-Language"), errs)
+}
+
+// ValidateRequest validates a struct decoded from the request body and
+// returns the input validation problem with the issues in the language of
+// the request.  Other values aren't validated.  It is a problems.Validator
+// so it can be given to problems.DecodeJSON with problems.WithValidator.
+func (v *Validation) ValidateRequest(r *http.Request, dst interface{}) error {
+	value := reflect.ValueOf(dst)
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+	if prob := v.FromError(r.Header.Get("Accept-Language"), v.Validate.Struct(value.Interface())); prob != nil {
+		return prob
+	}
+	return nil
+}
@@ -0,0 +1,253 @@
+package standard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/locales/de"
+	"github.com/go-playground/validator/v10"
+	"tjdavis.dev/problems"
+)
+
+type testUser struct {
+	Name  string `validate:"required"`
+	Color string `validate:"required,primary"`
+}
+
+func testValidation(t *testing.T) *Validation {
+	v, err := NewValidation(validator.New())
+	if err != nil {
+		t.Fatalf("NewValidation() error = %v", err)
+	}
+	_ = v.Validate.RegisterValidation("primary", func(fl validator.FieldLevel) bool {
+		color := fl.Field().String()
+		return color == "red" || color == "green" || color == "blue"
+	})
+	return v
+}
+
+func TestValidation_ResponseFor(t *testing.T) {
+	defer func(v *Validation) { DefaultValidation = v }(DefaultValidation)
+	v := testValidation(t)
+	DefaultValidation = v
+	if err := v.AddLocale(de.New(), nil); err != nil {
+		t.Fatalf("AddLocale() error = %v", err)
+	}
+	for locale, text := range map[string]string{
+		"en": "{0} must be a primary color",
+		"fr": "{0} doit être une couleur primaire",
+		"de": "{0} muss eine Primärfarbe sein",
+	} {
+		if err := v.RegisterTagMessage("primary", locale, text); err != nil {
+			t.Fatalf("RegisterTagMessage() error = %v", err)
+		}
+	}
+	if err := v.RegisterTagMessage("primary", "xx", "{0}"); err == nil {
+		t.Error("RegisterTagMessage() accepted an unknown language")
+	}
+
+	tests := []struct {
+		name     string
+		language string
+		want     []string
+	}{
+		{
+			name:     "Test English",
+			language: "",
+			want:     []string{"Name is a required field", "Color must be a primary color"},
+		},
+		{
+			name:     "Test French",
+			language: "fr-CH, en;q=0.5",
+			want:     []string{"Name est un champ obligatoire", "Color doit être une couleur primaire"},
+		},
+		{
+			name:     "Test custom language",
+			language: "de",
+			want:     []string{"Color muss eine Primärfarbe sein"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Validate.Struct(testUser{Color: "purple"})
+			r := httptest.NewRequest(http.MethodPost, "/users", nil)
+			r.Header.Set("Accept-Language", tt.language)
+			prob := v.ResponseFor(r, err.(validator.ValidationErrors))
+			var details []string
+			for _, issue := range prob.Issues() {
+				details = append(details, issue.Detail)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(strings.Join(details, "\n"), want) {
+					t.Errorf("ResponseFor() issues = %v, want %v", details, want)
+				}
+			}
+		})
+	}
+}
+
+type testSignup struct {
+	Email string `json:"email" validate:"required,email"`
+	Age   int    `json:"age" validate:"min=18"`
+}
+
+func TestDecodeJSON(t *testing.T) {
+	r := httptest.NewRequest("POST", "/signup", strings.NewReader(`{"email": "nobody", "age": "17", "plan": "pro"}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Accept-Language", "fr")
+	err := problems.DecodeJSON(httptest.NewRecorder(), r, &testSignup{}, problems.DisallowUnknownFields())
+	prob, ok := err.(*problems.Problem)
+	if !ok {
+		t.Fatalf("DecodeJSON() error = %v, want a problem", err)
+	}
+	want := []struct{ typeURI, pointer, detail string }{
+		{TypeSchemaViolation, "/age", "age must be an integer, not string"},
+		{TypeSchemaViolation, "/email", "email doit être une adresse email valide"},
+		{TypeUnknownParameter, "/plan", "plan is not a known field"},
+	}
+	issues := prob.Issues()
+	if len(issues) != len(want) {
+		t.Fatalf("DecodeJSON() issues = %v, want %d", issues, len(want))
+	}
+	for i, issue := range issues {
+		if issue.Type != want[i].typeURI || issue.Pointer != want[i].pointer || issue.Detail != want[i].detail {
+			t.Errorf("DecodeJSON() issue %d = %v %v %q", i, issue.Type, issue.Pointer, issue.Detail)
+		}
+	}
+	localized := prob.Localize("fr", nil)
+	issue := localized.Issues()[2]
+	if issue.Detail != "plan n'est pas un champ connu" {
+		t.Errorf("DecodeJSON() French issue detail = %q", issue.Detail)
+	}
+}
+
+func TestDecodeJSON_Language(t *testing.T) {
+	tests := []struct {
+		language string
+		want     string
+		detail   string
+	}{
+		{"fr", "fr", "email doit être une adresse email valide"},
+		// German has problem translations but no validator translations and
+		// Japanese the other way round, so both use the English text, which
+		// isn't a translation
+		{"de", "", "email must be a valid email address"},
+		{"ja", "", "email must be a valid email address"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.language, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/signup", strings.NewReader(`{"email": "nobody", "age": 18}`))
+			r.Header.Set("Content-Type", "application/json")
+			r.Header.Set("Accept-Language", tt.language)
+			prob, ok := problems.DecodeJSON(httptest.NewRecorder(), r, &testSignup{}).(*problems.Problem)
+			if !ok {
+				t.Fatal("DecodeJSON() returned no problem")
+			}
+			w := httptest.NewRecorder()
+			_ = prob.Render(w, r)
+			var body struct {
+				Issues []problems.Issue `json:"issues"`
+			}
+			_ = json.Unmarshal(w.Body.Bytes(), &body)
+			if got := w.Header().Get("Content-Language"); got != tt.want {
+				t.Errorf("Content-Language = %v, want %v", got, tt.want)
+			}
+			if len(body.Issues) != 1 || body.Issues[0].Detail != tt.detail {
+				t.Errorf("Render() issues = %+v, want %q", body.Issues, tt.detail)
+			}
+		})
+	}
+}
+
+type testPeriod struct {
+	StartDate string `json:"startDate"`
+	EndDate   string `json:"endDate"`
+}
+
+type testBooking struct {
+	Guest  string     `json:"guest" validate:"required"`
+	Period testPeriod `json:"period"`
+}
+
+func TestValidation_FromError(t *testing.T) {
+	const typeInvalidPeriod = "urn:problem-type:input-validation:invalidPeriod"
+	v := testValidation(t)
+	v.Validate.RegisterStructValidation(func(sl validator.StructLevel) {
+		booking := sl.Current().Interface().(testBooking)
+		if booking.Period.EndDate < booking.Period.StartDate {
+			sl.ReportError(booking.Period, "period", "Period", "invalidPeriod", "")
+		}
+	}, testBooking{})
+	v.RegisterIssueType("invalidPeriod", typeInvalidPeriod, func(fe validator.FieldError, issue *problems.Issue) {
+		issue.Extensions = map[string]interface{}{"rule": "endDate >= startDate"}
+	})
+	if err := v.RegisterTagMessage("invalidPeriod", "en", "endDate should be after startDate"); err != nil {
+		t.Fatalf("RegisterTagMessage() error = %v", err)
+	}
+
+	booking := testBooking{Period: testPeriod{StartDate: "2020-12-31", EndDate: "2020-01-01"}}
+	prob := v.FromError("en", fmt.Errorf("booking: %w", v.Validate.Struct(booking)))
+	if prob == nil || prob.Status != 400 {
+		t.Fatalf("FromError() = %v, want an input validation problem", prob)
+	}
+	var period problems.Issue
+	for _, issue := range prob.Issues() {
+		if issue.Type == typeInvalidPeriod {
+			period = issue
+		}
+	}
+	if period.Name != "period" || period.Pointer != "/period" {
+		t.Errorf("FromError() invalidPeriod issue name = %v", period.Name)
+	}
+	if period.Detail != "endDate should be after startDate" || period.Extensions["rule"] != "endDate >= startDate" {
+		t.Errorf("FromError() invalidPeriod issue = %+v", period)
+	}
+	if _, ok := period.Value.(testPeriod); !ok {
+		t.Errorf("FromError() invalidPeriod value = %#v", period.Value)
+	}
+
+	custom := problems.New(409, "Already booked")
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		typ    string
+	}{
+		{"Test nil", v.Validate.Struct(testBooking{Guest: "a"}), 0, ""},
+		{"Test nil struct", v.Validate.Struct(nil), 500, TypeInternalServerError},
+		{"Test not a struct", v.Validate.Struct(42), 500, TypeInternalServerError},
+		{"Test empty errors", validator.ValidationErrors{}, 500, TypeInternalServerError},
+		{"Test problem", custom, 409, "about:blank"},
+		{"Test other error", fmt.Errorf("database closed"), 500, TypeInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prob := v.FromError("en", tt.err)
+			if tt.status == 0 {
+				if prob != nil {
+					t.Errorf("FromError() = %v, want nil", prob)
+				}
+				return
+			}
+			if prob == nil || prob.Status != tt.status || (prob.Type != tt.typ && !(tt.typ == "about:blank" && prob.Type == "")) {
+				t.Errorf("FromError() = %+v, want %d %s", prob, tt.status, tt.typ)
+			}
+		})
+	}
+	closed := errors.New("database closed")
+	prob = v.FromError("en", closed)
+	if !errors.Is(prob, closed) {
+		t.Errorf("FromError() = %+v, want the error wrapped", prob)
+	}
+	if data, err := json.Marshal(prob); err != nil || strings.Contains(string(data), "database") {
+		t.Errorf("FromError() = %s, want no text of the error", data)
+	}
+	if FromValidationError(nil) != nil {
+		t.Error("FromValidationError(nil) is not nil")
+	}
+}
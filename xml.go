@@ -0,0 +1,108 @@
+package problems
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+const (
+	// ProblemXMLMediaType is the media type for a Problem rendered as XML
+	ProblemXMLMediaType = "application/problem+xml"
+	// ProblemXMLNamespace is the namespace of the problem XML element (RFC7807 Appendix A)
+	ProblemXMLNamespace = "urn:ietf:rfc:7807"
+)
+
+// marshalXML renders the problem members in the XML format defined by
+// RFC7807 Appendix A.  Arrays are rendered as repeated `i` elements and
+// objects as nested elements.
+func marshalXML(members map[string]interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	start := xml.StartElement{
+		Name: xml.Name{Local: "problem"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: ProblemXMLNamespace}},
+	}
+	if err := encodeXMLToken(enc, start); err != nil {
+		return nil, err
+	}
+	if err := encodeXMLMembers(enc, members); err != nil {
+		return nil, err
+	}
+	if err := encodeXMLToken(enc, start.End()); err != nil {
+		return nil, err
+	}
+	if err := enc.Flush(); err != nil {
+		return nil, FromError(err)
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeXMLMembers(enc *xml.Encoder, members map[string]interface{}) error {
+	keys := make([]string, 0, len(members))
+	for k := range members {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := encodeXMLValue(enc, k, members[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeXMLValue(enc *xml.Encoder, name string, value interface{}) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if err := encodeXMLToken(enc, start); err != nil {
+		return err
+	}
+	if err := encodeXMLContent(enc, value); err != nil {
+		return err
+	}
+	return encodeXMLToken(enc, start.End())
+}
+
+func encodeXMLToken(enc *xml.Encoder, token xml.Token) error {
+	if err := enc.EncodeToken(token); err != nil {
+		return FromError(err)
+	}
+	return nil
+}
+
+func encodeXMLContent(enc *xml.Encoder, value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch reflect.Indirect(reflect.ValueOf(value)).Kind() {
+	case reflect.Map, reflect.Struct:
+		// round trip through JSON so structs use their json names
+		data, err := json.Marshal(value)
+		if err != nil {
+			return FromError(err)
+		}
+		var members map[string]interface{}
+		if err := json.Unmarshal(data, &members); err != nil {
+			return FromError(err)
+		}
+		return encodeXMLMembers(enc, members)
+	case reflect.Slice, reflect.Array:
+		if b, ok := value.([]byte); ok {
+			return encodeXMLToken(enc, xml.CharData(b))
+		}
+		items := reflect.ValueOf(value)
+		for i := 0; i < items.Len(); i++ {
+			if err := encodeXMLValue(enc, "i", items.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return encodeXMLToken(enc, xml.CharData(fmt.Sprint(value)))
+	}
+}